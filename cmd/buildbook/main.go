@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cstuartroe/minimax/connect_four"
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/mancala"
+	"github.com/cstuartroe/minimax/minimaxer"
+	"github.com/cstuartroe/minimax/tictactoe"
+)
+
+func build[State games.GameState](game games.Game[State], m *minimaxer.Minimaxer[State], plies int, tolerance int, out string) error {
+	book := minimaxer.BuildBook(game, m, plies, tolerance)
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	n, err := book.WriteTo(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d positions (%d bytes) to %s\n", len(book.Positions), n, out)
+	return nil
+}
+
+func main() {
+	game := flag.String("game", "connect_four", "game to build a book for: connect_four, tictactoe or mancala")
+	plies := flag.Int("plies", 4, "how many moves deep the book goes")
+	depth := flag.Int("depth", 8, "search depth for rating each position's moves")
+	tolerance := flag.Int("tolerance", 0, "how far below the best move, in evaluation units, a move can score and still be included")
+	out := flag.String("out", "", "file to write the book to (default <game>.book)")
+	flag.Parse()
+
+	if *out == "" {
+		*out = *game + ".book"
+	}
+
+	var err error
+	switch *game {
+	case "connect_four":
+		g := connect_four.ConnectFour()
+		m := minimaxer.NewMinimaxer(g, *depth).WithAlphaBeta().WithEvaluator(connect_four.Evaluator())
+		err = build(g, m, *plies, *tolerance, *out)
+	case "tictactoe":
+		g := tictactoe.TicTacToe()
+		err = build(g, minimaxer.NewMinimaxer(g, *depth).WithAlphaBeta(), *plies, *tolerance, *out)
+	case "mancala":
+		g := mancala.MancalaGame(6, 4)
+		m := minimaxer.NewMinimaxer(g, *depth).WithAlphaBeta().WithEvaluator(mancala.Evaluator())
+		err = build(g, m, *plies, *tolerance, *out)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown game %q\n", *game)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
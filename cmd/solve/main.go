@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/nim"
+	"github.com/cstuartroe/minimax/peg_solitaire"
+	"github.com/cstuartroe/minimax/solver"
+	"github.com/cstuartroe/minimax/tictactoe"
+)
+
+func solve[State games.GameState](game games.Game[State], out string) error {
+	table, err := solver.Solve(game)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	n, err := table.WriteTo(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d positions (%d bytes) to %s\n", table.Len(), n, out)
+	return nil
+}
+
+func main() {
+	game := flag.String("game", "tictactoe", "game to solve: tictactoe, nim or peg_solitaire")
+	piles := flag.String("piles", "3,4,5", "comma-separated pile sizes for nim")
+	maxTake := flag.Int("max-take", 0, "most objects nim lets you take at once, or 0 for no limit")
+	misere := flag.Bool("misere", false, "play nim misere")
+	out := flag.String("out", "", "file to write the table to (default <game>.solved)")
+	flag.Parse()
+
+	if *out == "" {
+		*out = *game + ".solved"
+	}
+
+	var err error
+	switch *game {
+	case "tictactoe":
+		err = solve(tictactoe.TicTacToe(), *out)
+	case "nim":
+		state := nim.NimState{}
+		for _, pile := range strings.Split(*piles, ",") {
+			n, convErr := strconv.Atoi(strings.TrimSpace(pile))
+			if convErr != nil {
+				fmt.Fprintf(os.Stderr, "bad pile size %q\n", pile)
+				os.Exit(2)
+			}
+			state = append(state, n)
+		}
+		err = solve(nim.NimGame(state, *maxTake, *misere), *out)
+	case "peg_solitaire":
+		err = solve(peg_solitaire.TrianglePegSolitaire(), *out)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown game %q\n", *game)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
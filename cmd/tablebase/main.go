@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cstuartroe/minimax/mancala"
+)
+
+func write(tb *mancala.Tablebase, out string) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	n, err := tb.WriteTo(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d positions (%d bytes) to %s\n", tb.Len(), n, out)
+	return nil
+}
+
+func main() {
+	runLength := flag.Int("run-length", 6, "pits on each side of the Mancala board")
+	seeds := flag.Int("seeds", 12, "most seeds left in the pits for a position to be included")
+	out := flag.String("out", "", "file to write the tablebase to (default mancala-<run-length>-<seeds>.tb)")
+	flag.Parse()
+
+	if *out == "" {
+		*out = fmt.Sprintf("mancala-%d-%d.tb", *runLength, *seeds)
+	}
+
+	tb, err := mancala.BuildTablebase(*runLength, *seeds)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := write(tb, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
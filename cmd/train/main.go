@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cstuartroe/minimax/connect_four"
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/mancala"
+	"github.com/cstuartroe/minimax/training"
+)
+
+type options struct {
+	games   int
+	depth   int
+	rate    float64
+	lambda  float64
+	epsilon float64
+	seed    int64
+	in      string
+	out     string
+}
+
+func train[State games.GameState](game games.Game[State], featurizer training.Featurizer[State], limit float64, opts options) error {
+	trainer := training.NewTrainer(game, featurizer).
+		WithLimit(limit).
+		WithSearchDepth(opts.depth).
+		WithLearningRate(opts.rate).
+		WithLambda(opts.lambda).
+		WithExploration(opts.epsilon).
+		WithSeed(opts.seed)
+
+	if opts.in != "" {
+		f, err := os.Open(opts.in)
+		if err != nil {
+			return err
+		}
+		evaluator, err := training.ReadLinearEvaluator(f, featurizer)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		trainer.WithWeights(evaluator.Weights)
+	}
+
+	for done := 0; done < opts.games; {
+		batch := 100
+		if opts.games-done < batch {
+			batch = opts.games - done
+		}
+		trainer.Train(batch)
+		done += batch
+		fmt.Println(trainer.Progress())
+	}
+
+	f, err := os.Create(opts.out)
+	if err != nil {
+		return err
+	}
+
+	_, err = trainer.Evaluator().WriteTo(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote weights %.3f to %s\n", trainer.Evaluator().Weights, opts.out)
+	return nil
+}
+
+func main() {
+	game := flag.String("game", "connect_four", "game to train an evaluator for: connect_four or mancala")
+	opts := options{}
+	flag.IntVar(&opts.games, "games", 1000, "self-play games to train on")
+	flag.IntVar(&opts.depth, "depth", 2, "search depth of the self-play players")
+	flag.Float64Var(&opts.rate, "rate", 0.01, "learning rate")
+	flag.Float64Var(&opts.lambda, "lambda", 0.7, "how far TD corrections carry back, from 0 to 1")
+	flag.Float64Var(&opts.epsilon, "epsilon", 0.1, "chance of a random move in self-play")
+	flag.Int64Var(&opts.seed, "seed", time.Now().UnixNano(), "seed for every random choice")
+	flag.StringVar(&opts.in, "in", "", "weights file to continue training from")
+	flag.StringVar(&opts.out, "out", "", "file to write the weights to (default <game>.weights)")
+	flag.Parse()
+
+	if opts.out == "" {
+		opts.out = *game + ".weights"
+	}
+
+	var err error
+	switch *game {
+	case "connect_four":
+		err = train(connect_four.ConnectFour(), training.Featurizer[connect_four.ConnectFourState](connect_four.Featurizer{}), 1, opts)
+	case "mancala":
+		// Mancala has no single winning Score to keep estimates under.
+		err = train(mancala.MancalaGame(6, 4), training.Featurizer[mancala.MancalaState](mancala.Featurizer{}), 0, opts)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown game %q\n", *game)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -2,6 +2,7 @@ package connect_four
 
 import (
 	"fmt"
+	"math/rand"
 
 	"github.com/cstuartroe/minimax/games"
 )
@@ -40,6 +41,33 @@ func (s ConnectFourState) String() string {
 	return out
 }
 
+var zobristKeys [6][7][3]uint64 = func() [6][7][3]uint64 {
+	r := rand.New(rand.NewSource(4))
+	keys := [6][7][3]uint64{}
+	for y := range keys {
+		for x := range keys[y] {
+			for piece := range keys[y][x] {
+				keys[y][x][piece] = r.Uint64()
+			}
+		}
+	}
+	return keys
+}()
+
+// Hash is a Zobrist hash of the board: the XOR of a fixed random key for each
+// piece in each square.
+func (s ConnectFourState) Hash() uint64 {
+	var h uint64
+	for y := range s {
+		for x, piece := range s[y] {
+			if piece != CFBlank {
+				h ^= zobristKeys[y][x][piece]
+			}
+		}
+	}
+	return h
+}
+
 func (s ConnectFourState) copy() ConnectFourState {
 	out := ConnectFourState{}
 
@@ -105,6 +133,41 @@ func getAllStreaks() [][]Position {
 
 var allStreaks [][]Position = getAllStreaks()
 
+// streaksThrough lists, for each square, the lines of four that include it.
+var streaksThrough [6][7][][]Position = func() [6][7][][]Position {
+	out := [6][7][][]Position{}
+	for _, streak := range allStreaks {
+		for _, pos := range streak {
+			out[pos.y][pos.x] = append(out[pos.y][pos.x], streak)
+		}
+	}
+	return out
+}()
+
+// isThreat reports whether playing piece at p, in a board where it's already
+// been placed, either makes three in a line with the fourth square empty or
+// fills the empty square of a line where the other player had three.
+func isThreat(s ConnectFourState, p Position, piece ConnectFourPiece) bool {
+	for _, streak := range streaksThrough[p.y][p.x] {
+		mine, theirs, blank := 0, 0, 0
+		for _, pos := range streak {
+			switch s.at(pos) {
+			case piece:
+				mine++
+			case CFBlank:
+				blank++
+			default:
+				theirs++
+			}
+		}
+
+		if (mine == 3 && blank == 1) || (mine == 1 && theirs == 3) {
+			return true
+		}
+	}
+	return false
+}
+
 func getScore(s ConnectFourState) int {
 	for _, streak := range allStreaks {
 		piece := s.at(streak[0])
@@ -152,6 +215,7 @@ func getMoves(prospect games.Prospect[ConnectFourState]) []games.Move[ConnectFou
 				Summary:       fmt.Sprintf("Go in column #%d", x),
 				State:         newState,
 				RetainControl: false,
+				Noisy:         isThreat(newState, Position{x, int8(row)}, piece),
 			})
 		}
 	}
@@ -173,6 +237,133 @@ func (cg _ConnectFour) Describe(prospect games.Prospect[ConnectFourState]) games
 	}
 }
 
+// MovePriority prefers moves in the more central columns.
+func (cg _ConnectFour) MovePriority(prospect games.Prospect[ConnectFourState], move games.Move[ConnectFourState]) int {
+	for y := range move.State {
+		for x := range move.State[y] {
+			if move.State[y][x] != prospect.State[y][x] {
+				return columnWeights[x]
+			}
+		}
+	}
+	return 0
+}
+
+// Canonical picks whichever of the board and its left-right mirror image has
+// the lower hash.
+func (cg _ConnectFour) Canonical(s ConnectFourState) ConnectFourState {
+	mirror := ConnectFourState{}
+	for y := range s {
+		for x := range s[y] {
+			mirror[y][6-x] = s[y][x]
+		}
+	}
+
+	if mirror.Hash() < s.Hash() {
+		return mirror
+	}
+	return s
+}
+
 func ConnectFour() games.Game[ConnectFourState] {
 	return _ConnectFour{}
 }
+
+// streakWeights values a line of four by how many pieces one player has in it,
+// provided the other player has none there.
+var streakWeights [5]int = [5]int{0, 1, 10, 50, 0}
+
+// columnWeights values a piece by how central its column is.
+var columnWeights [7]int = [7]int{0, 4, 8, 12, 8, 4, 0}
+
+// openLines counts the lines of four that hold pieces of only one player, by
+// how many pieces are in them: red[n] is the number of lines with n red pieces
+// and no yellow ones.
+func openLines(s ConnectFourState) (red, yellow [5]int) {
+	for _, streak := range allStreaks {
+		r, y := 0, 0
+		for _, pos := range streak {
+			if s.at(pos) == CFRed {
+				r++
+			} else if s.at(pos) == CFYellow {
+				y++
+			}
+		}
+
+		if y == 0 && r > 0 {
+			red[r]++
+		} else if r == 0 && y > 0 {
+			yellow[y]++
+		}
+	}
+	return red, yellow
+}
+
+// centralization is how much more central red's pieces are than yellow's, by
+// columnWeights.
+func centralization(s ConnectFourState) int {
+	center := 0
+	for y := range s {
+		for x, piece := range s[y] {
+			if piece == CFRed {
+				center += columnWeights[x]
+			} else if piece == CFYellow {
+				center -= columnWeights[x]
+			}
+		}
+	}
+	return center
+}
+
+type evaluator struct{}
+
+// Evaluator scores undecided Connect Four positions by the open threes and
+// twos each player has, meaning lines of four the other player hasn't blocked,
+// and by how many of each player's pieces are near the center.
+func Evaluator() games.Evaluator[ConnectFourState] {
+	return evaluator{}
+}
+
+func (evaluator) Evaluate(prospect games.Prospect[ConnectFourState]) int {
+	s := prospect.State
+	score := centralization(s)
+
+	red, yellow := openLines(s)
+	for n, weight := range streakWeights {
+		score += weight * (red[n] - yellow[n])
+	}
+
+	if score >= games.EvaluationScale {
+		score = games.EvaluationScale - 1
+	} else if score <= -games.EvaluationScale {
+		score = 1 - games.EvaluationScale
+	}
+	return score
+}
+
+// Featurizer describes Connect Four positions for training an evaluator: a
+// constant, whose turn it is, the differences in open ones, twos and threes
+// between the players, and the difference in how central their pieces are.
+type Featurizer struct{}
+
+func (Featurizer) FeatureCount() int {
+	return 6
+}
+
+func (Featurizer) Features(prospect games.Prospect[ConnectFourState]) []float64 {
+	s := prospect.State
+
+	turn := -1.0
+	if prospect.FirstAgent {
+		turn = 1
+	}
+
+	red, yellow := openLines(s)
+	open := [4]float64{}
+	for n := 1; n < 4; n++ {
+		open[n] = float64(red[n] - yellow[n])
+	}
+	center := float64(centralization(s))
+
+	return []float64{1, turn, open[1] / 10, open[2] / 5, open[3] / 2, center / 50}
+}
@@ -1,7 +1,9 @@
 package gameplay
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 
 	"github.com/cstuartroe/minimax/games"
 )
@@ -12,6 +14,19 @@ type Player[State games.GameState] interface {
 	Comment() string
 }
 
+// StatsReporter is an optional interface for players that can describe the
+// work they did choosing their last move. Verbose games print the report after
+// each of their moves, unless it's empty.
+type StatsReporter interface {
+	ReportStats() string
+}
+
+// ContextPlayer is an optional interface for players whose move choice can be
+// cancelled, which PlayContext uses when a player implements it.
+type ContextPlayer[State games.GameState] interface {
+	ChooseMoveContext(context.Context, games.Prospect[State]) (games.Move[State], error)
+}
+
 type HumanPlayer[State games.GameState] struct {
 	name string
 	game games.Game[State]
@@ -26,101 +41,133 @@ func (p HumanPlayer[State]) Name() string {
 }
 
 func (p HumanPlayer[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
-	sd := p.game.Describe(prospect)
-
-	fmt.Println("Possible moves:")
-	for i, move := range sd.Moves {
-		fmt.Printf("%d: %s\n", i, move.Summary)
+	moves := p.game.Describe(prospect).Moves
+	summaries := make([]string, len(moves))
+	for i, move := range moves {
+		summaries[i] = move.Summary
 	}
-	fmt.Print("Choose: ")
-	var choice int
-	_, err := fmt.Scan(&choice)
+	return moves[promptMove(summaries)]
+}
+
+func (p HumanPlayer[State]) Comment() string {
+	return ""
+}
+
+// promptMove lists the moves by summary and asks until the user picks one.
+func promptMove(summaries []string) int {
+	for {
+		fmt.Println("Possible moves:")
+		for i, summary := range summaries {
+			fmt.Printf("%d: %s\n", i, summary)
+		}
+		fmt.Print("Choose: ")
+		var choice int
+		_, err := fmt.Scan(&choice)
 
-	if err != nil || choice >= len(sd.Moves) {
+		if err == nil && choice >= 0 && choice < len(summaries) {
+			return choice
+		}
 		fmt.Println("Invalid entry.")
-		return p.ChooseMove(prospect)
 	}
+}
 
-	return sd.Moves[choice]
+// twoPlayer adapts a Player to the MultiPlayer interface, for a game adapted
+// by games.FromTwoPlayer.
+type twoPlayer[State games.GameState] struct {
+	player Player[State]
 }
 
-func (p HumanPlayer[State]) Comment() string {
-	return ""
+func (p twoPlayer[State]) Name() string {
+	return p.player.Name()
 }
 
-type Gameplay[State games.GameState] struct {
-	game            games.Game[State]
-	player1         Player[State]
-	player2         Player[State]
-	currentProspect games.Prospect[State]
+func (p twoPlayer[State]) prospect(prospect games.MultiProspect[State]) games.Prospect[State] {
+	return games.Prospect[State]{State: prospect.State, FirstAgent: prospect.Player == 0}
 }
 
-func NewGameplay[State games.GameState](game games.Game[State], player1 Player[State], player2 Player[State]) Gameplay[State] {
-	return Gameplay[State]{
-		game:    game,
-		player1: player1,
-		player2: player2,
-		currentProspect: games.Prospect[State]{
-			State:      game.InitialState(),
-			FirstAgent: true,
-		},
-	}
+func (p twoPlayer[State]) ChooseMove(prospect games.MultiProspect[State]) games.MultiMove[State] {
+	return games.FromTwoPlayerMove(p.player.ChooseMove(p.prospect(prospect)), prospect.Player)
 }
 
-func (gp *Gameplay[State]) makeMove(move games.Move[State]) {
-	nextAgent := !gp.currentProspect.FirstAgent
-	if move.RetainControl {
-		nextAgent = gp.currentProspect.FirstAgent
+func (p twoPlayer[State]) ChooseMoveContext(ctx context.Context, prospect games.MultiProspect[State]) (games.MultiMove[State], error) {
+	cp, ok := p.player.(ContextPlayer[State])
+	if !ok {
+		return p.ChooseMove(prospect), nil
 	}
+	move, err := cp.ChooseMoveContext(ctx, p.prospect(prospect))
+	return games.FromTwoPlayerMove(move, prospect.Player), err
+}
+
+func (p twoPlayer[State]) Comment() string {
+	return p.player.Comment()
+}
 
-	gp.currentProspect = games.Prospect[State]{
-		State:      move.State,
-		FirstAgent: nextAgent,
+func (p twoPlayer[State]) ReportStats() string {
+	if reporter, ok := p.player.(StatsReporter); ok {
+		return reporter.ReportStats()
 	}
+	return ""
 }
 
-func (gp Gameplay[State]) done() bool {
-	return len(gp.game.Describe(gp.currentProspect).Moves) == 0
+// Gameplay plays a two-player game, as a MultiGameplay of the game adapted by
+// games.FromTwoPlayer, with player1 as the first agent.
+type Gameplay[State games.GameState] struct {
+	players [2]Player[State]
+	multi   MultiGameplay[State]
 }
 
-func (gp *Gameplay[State]) Play(verbose bool) int {
-	log := func(format string, a ...any) (n int, err error) { return 0, nil }
-	if verbose {
-		log = fmt.Printf
+func NewGameplay[State games.GameState](game games.Game[State], player1 Player[State], player2 Player[State]) Gameplay[State] {
+	return Gameplay[State]{
+		players: [2]Player[State]{player1, player2},
+		multi:   NewMultiGameplay[State](games.FromTwoPlayer(game), twoPlayer[State]{player1}, twoPlayer[State]{player2}),
 	}
+}
 
-	for !gp.done() {
-		player := gp.player2
-		if gp.currentProspect.FirstAgent {
-			player = gp.player1
-		}
+// WithSeed makes chance outcomes come from a source seeded with seed. Seeding
+// the players as well makes a whole game replayable.
+func (gp Gameplay[State]) WithSeed(seed int64) Gameplay[State] {
+	return gp.WithRandSource(rand.NewSource(seed))
+}
 
-		log("%s's turn\n", player.Name())
-		log("Current state:\n")
-		log("%s\n", gp.currentProspect.State.String())
-		move := player.ChooseMove(gp.currentProspect)
-		log("%s chose %s\n", player.Name(), move.Summary)
-		if comment := player.Comment(); comment != "" {
-			log("%s says: %s\n", player.Name(), comment)
-		}
+// WithRandSource makes chance outcomes come from src.
+func (gp Gameplay[State]) WithRandSource(src rand.Source) Gameplay[State] {
+	gp.multi = gp.multi.WithRandSource(src)
+	return gp
+}
+
+func (gp *Gameplay[State]) Play(verbose bool) int {
+	score, _ := gp.PlayContext(context.Background(), verbose)
+	return score
+}
 
-		fmt.Println()
+// PlayContext is like Play, but gives up when ctx is done, returning ctx's
+// error or the error from a player's ChooseMoveContext.
+func (gp *Gameplay[State]) PlayContext(ctx context.Context, verbose bool) (int, error) {
+	log := logger(verbose)
 
-		gp.makeMove(move)
+	scores, err := gp.multi.play(ctx, log)
+	if err != nil {
+		return 0, err
 	}
 
-	log("Final state:\n")
-	log("%s\n", gp.currentProspect.State.String())
-
-	score := gp.game.Describe(gp.currentProspect).Score
+	score := scores[0]
 	log("Game score: %d\n", score)
 	if score > 0 {
-		log("%s wins!\n", gp.player1.Name())
+		log("%s wins!\n", gp.players[0].Name())
 	} else if score < 0 {
-		log("%s wins!\n", gp.player2.Name())
+		log("%s wins!\n", gp.players[1].Name())
 	} else {
 		log("It's a draw.\n")
 	}
 
-	return score
+	return score, nil
+}
+
+// logger returns fmt.Printf for verbose games, and otherwise a function that
+// prints nothing.
+func logger(verbose bool) func(format string, a ...any) (int, error) {
+	if verbose {
+		return fmt.Printf
+	}
+	return func(format string, a ...any) (int, error) { return 0, nil }
 }
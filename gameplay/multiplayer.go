@@ -0,0 +1,189 @@
+package gameplay
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+type MultiPlayer[State games.GameState] interface {
+	Name() string
+	ChooseMove(games.MultiProspect[State]) games.MultiMove[State]
+	Comment() string
+}
+
+// MultiContextPlayer is ContextPlayer for games with any number of players.
+type MultiContextPlayer[State games.GameState] interface {
+	ChooseMoveContext(context.Context, games.MultiProspect[State]) (games.MultiMove[State], error)
+}
+
+type MultiHumanPlayer[State games.GameState] struct {
+	name string
+	game games.MultiGame[State]
+}
+
+func NewMultiHumanPlayer[State games.GameState](name string, game games.MultiGame[State]) MultiHumanPlayer[State] {
+	return MultiHumanPlayer[State]{name, game}
+}
+
+func (p MultiHumanPlayer[State]) Name() string {
+	return p.name
+}
+
+func (p MultiHumanPlayer[State]) ChooseMove(prospect games.MultiProspect[State]) games.MultiMove[State] {
+	moves := p.game.Describe(prospect).Moves
+	summaries := make([]string, len(moves))
+	for i, move := range moves {
+		summaries[i] = move.Summary
+	}
+	return moves[promptMove(summaries)]
+}
+
+func (p MultiHumanPlayer[State]) Comment() string {
+	return ""
+}
+
+// MultiGameplay plays a game for any number of players, one per player index.
+type MultiGameplay[State games.GameState] struct {
+	game            games.MultiGame[State]
+	players         []MultiPlayer[State]
+	rng             *rand.Rand
+	currentProspect games.MultiProspect[State]
+}
+
+func NewMultiGameplay[State games.GameState](game games.MultiGame[State], players ...MultiPlayer[State]) MultiGameplay[State] {
+	if len(players) != game.Players() {
+		panic(fmt.Sprintf("game needs %d players, got %d", game.Players(), len(players)))
+	}
+
+	return MultiGameplay[State]{
+		game:    game,
+		players: players,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		currentProspect: games.MultiProspect[State]{
+			State:  game.InitialState(),
+			Player: 0,
+		},
+	}
+}
+
+// WithSeed makes chance outcomes come from a source seeded with seed.
+func (gp MultiGameplay[State]) WithSeed(seed int64) MultiGameplay[State] {
+	return gp.WithRandSource(rand.NewSource(seed))
+}
+
+// WithRandSource makes chance outcomes come from src.
+func (gp MultiGameplay[State]) WithRandSource(src rand.Source) MultiGameplay[State] {
+	gp.rng = rand.New(src)
+	return gp
+}
+
+func (gp *MultiGameplay[State]) makeMove(move games.MultiMove[State]) {
+	gp.currentProspect = games.MultiProspect[State]{
+		State:  move.State,
+		Player: move.NextPlayer,
+	}
+}
+
+// Play plays the game to the end and returns each player's final score.
+func (gp *MultiGameplay[State]) Play(verbose bool) []int {
+	scores, _ := gp.PlayContext(context.Background(), verbose)
+	return scores
+}
+
+// PlayContext is like Play, but gives up when ctx is done, returning ctx's
+// error or the error from a player's ChooseMoveContext.
+func (gp *MultiGameplay[State]) PlayContext(ctx context.Context, verbose bool) ([]int, error) {
+	log := logger(verbose)
+
+	scores, err := gp.play(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+
+	best := 0
+	for i, player := range gp.players {
+		log("%s scored %d\n", player.Name(), scores[i])
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+
+	winners := 0
+	for _, score := range scores {
+		if score == scores[best] {
+			winners++
+		}
+	}
+	if winners == 1 {
+		log("%s wins!\n", gp.players[best].Name())
+	} else {
+		log("It's a tie.\n")
+	}
+
+	return scores, nil
+}
+
+// play plays the game to the end, logging each move and the final state, and
+// returns each player's final score. Two-player games are played here too,
+// adapted by games.FromTwoPlayer, and only report the result differently.
+func (gp *MultiGameplay[State]) play(ctx context.Context, log func(format string, a ...any) (int, error)) ([]int, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sd := gp.game.Describe(gp.currentProspect)
+
+		if len(sd.Outcomes) > 0 {
+			outcome := sd.Outcomes[games.PickOutcome(sd.Outcomes, gp.rng.Float64())]
+			log("Current state:\n")
+			log("%s\n", gp.currentProspect.State.String())
+			log("Chance: %s\n\n", outcome.Move.Summary)
+			gp.makeMove(outcome.Move)
+			continue
+		}
+
+		if len(sd.Moves) == 0 {
+			break
+		}
+
+		player := gp.players[gp.currentProspect.Player]
+
+		log("%s's turn\n", player.Name())
+		log("Current state:\n")
+		log("%s\n", gp.currentProspect.State.String())
+
+		var move games.MultiMove[State]
+		if cp, ok := player.(MultiContextPlayer[State]); ok {
+			var err error
+			move, err = cp.ChooseMoveContext(ctx, gp.currentProspect)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			move = player.ChooseMove(gp.currentProspect)
+		}
+		log("%s chose %s\n", player.Name(), move.Summary)
+		if comment := player.Comment(); comment != "" {
+			log("%s says: %s\n", player.Name(), comment)
+		}
+		if reporter, ok := player.(StatsReporter); ok {
+			if report := reporter.ReportStats(); report != "" {
+				log("%s stats: %s\n", player.Name(), report)
+			}
+		}
+
+		log("\n")
+
+		gp.makeMove(move)
+	}
+
+	log("Final state:\n")
+	log("%s\n", gp.currentProspect.State.String())
+
+	return gp.game.Describe(gp.currentProspect).Scores, nil
+}
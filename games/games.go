@@ -1,11 +1,22 @@
 package games
 
-import "fmt"
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
 
 type GameState interface {
 	String() string
 }
 
+// Hashable is an optional interface for game states that can compute a 64-bit
+// hash more cheaply than formatting themselves with String(). Equal states must
+// have equal hashes.
+type Hashable interface {
+	Hash() uint64
+}
+
 type Prospect[State GameState] struct {
 	State      State
 	FirstAgent bool
@@ -15,18 +26,130 @@ func (p Prospect[State]) String() string {
 	return fmt.Sprintf("%t%s", p.FirstAgent, p.State.String())
 }
 
+const firstAgentHash uint64 = 0x9e3779b97f4a7c15
+
+func stateHash[State GameState](state State) uint64 {
+	if hashable, ok := any(state).(Hashable); ok {
+		return hashable.Hash()
+	}
+
+	f := fnv.New64a()
+	f.Write([]byte(state.String()))
+	return f.Sum64()
+}
+
+// HashInts is an FNV-1a hash of a list of numbers, for Hashable states that
+// come down to one.
+func HashInts(values ...int) uint64 {
+	f := fnv.New64a()
+	var buf [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		f.Write(buf[:])
+	}
+	return f.Sum64()
+}
+
+// Hash identifies a prospect by its state's Hash if the state is Hashable, or
+// else by a hash of its String.
+func (p Prospect[State]) Hash() uint64 {
+	h := stateHash(p.State)
+	if p.FirstAgent {
+		h ^= firstAgentHash
+	}
+	return h
+}
+
+// After returns the prospect move leads to: its state, with the same player to
+// move if the move retains control and the other one otherwise.
+func (p Prospect[State]) After(move Move[State]) Prospect[State] {
+	firstAgent := p.FirstAgent
+	if !move.RetainControl {
+		firstAgent = !firstAgent
+	}
+	return Prospect[State]{State: move.State, FirstAgent: firstAgent}
+}
+
 type Move[State GameState] struct {
 	Summary       string
 	State         State
 	RetainControl bool
+	// Noisy marks a move that changes the position sharply, like a capture or
+	// a threat, after which an evaluation can't be trusted until things settle.
+	Noisy bool
 }
 
+// Outcome is one way a chance node can turn out, and how likely it is.
+type Outcome[State GameState] struct {
+	Move        Move[State]
+	Probability float64
+}
+
+// StateDescriptor describes a position. If Outcomes isn't empty, the position
+// is a chance node: rather than anyone choosing a move, one of the outcomes
+// happens at random, and Moves should be empty. A position with neither moves
+// nor outcomes is the end of the game.
 type StateDescriptor[State GameState] struct {
-	Score int
-	Moves []Move[State]
+	Score    int
+	Moves    []Move[State]
+	Outcomes []Outcome[State]
+}
+
+func (o Outcome[State]) probability() float64 {
+	return o.Probability
+}
+
+// outcome is either kind of chance node outcome, Outcome or MultiOutcome.
+type outcome interface {
+	probability() float64
+}
+
+// PickOutcome returns the index of the outcome that x, a number drawn
+// uniformly from [0, 1), falls on. It takes Outcomes or MultiOutcomes.
+func PickOutcome[O outcome](outcomes []O, x float64) int {
+	for i, outcome := range outcomes {
+		x -= outcome.probability()
+		if x < 0 {
+			return i
+		}
+	}
+	return len(outcomes) - 1
 }
 
 type Game[State GameState] interface {
 	InitialState() State
 	Describe(Prospect[State]) StateDescriptor[State]
 }
+
+// MovePrioritizer is an optional interface for games that can guess, without
+// searching, which moves are likely to be better. Searches try moves with
+// higher priority first.
+type MovePrioritizer[State GameState] interface {
+	MovePriority(Prospect[State], Move[State]) int
+}
+
+// Symmetric is an optional interface for games whose positions come in
+// equivalent variants, like rotations or reflections of a board. Canonical
+// returns the same state for every variant of a position, so that searches can
+// share what they learn about one with all the others.
+type Symmetric[State GameState] interface {
+	Canonical(State) State
+}
+
+// Oracle is an optional source of exact results, like an endgame tablebase.
+// Lookup returns the final Score the game from prospect ends with under
+// perfect play, if the oracle knows it.
+type Oracle[State GameState] interface {
+	Lookup(Prospect[State]) (int, bool)
+}
+
+// EvaluationScale is how many evaluation units make up one point of Score.
+const EvaluationScale = 1000
+
+// Evaluator estimates the eventual Score of a position that isn't over yet,
+// from the first agent's point of view, in units of 1/EvaluationScale of a
+// point. Estimates for positions that aren't clearly won should stay smaller in
+// magnitude than the game's winning Score times EvaluationScale.
+type Evaluator[State GameState] interface {
+	Evaluate(Prospect[State]) int
+}
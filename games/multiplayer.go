@@ -0,0 +1,93 @@
+package games
+
+import "fmt"
+
+// MultiProspect is a position in a game for any number of players, along with
+// which player is to move, counting from 0.
+type MultiProspect[State GameState] struct {
+	State  State
+	Player int
+}
+
+func (p MultiProspect[State]) String() string {
+	return fmt.Sprintf("%d%s", p.Player, p.State.String())
+}
+
+func (p MultiProspect[State]) Hash() uint64 {
+	return stateHash(p.State) ^ uint64(p.Player+1)*firstAgentHash
+}
+
+type MultiMove[State GameState] struct {
+	Summary    string
+	State      State
+	NextPlayer int
+}
+
+type MultiOutcome[State GameState] struct {
+	Move        MultiMove[State]
+	Probability float64
+}
+
+func (o MultiOutcome[State]) probability() float64 {
+	return o.Probability
+}
+
+// MultiStateDescriptor describes a position in a game for any number of
+// players. Scores has one entry per player, and each player wants their own to
+// be as high as possible. Moves and Outcomes work as in StateDescriptor.
+type MultiStateDescriptor[State GameState] struct {
+	Scores   []int
+	Moves    []MultiMove[State]
+	Outcomes []MultiOutcome[State]
+}
+
+type MultiGame[State GameState] interface {
+	Players() int
+	InitialState() State
+	Describe(MultiProspect[State]) MultiStateDescriptor[State]
+}
+
+type twoPlayerGame[State GameState] struct {
+	game Game[State]
+}
+
+// FromTwoPlayer adapts a two-player game to the MultiGame interface. The first
+// agent is player 0 and scores Score, and player 1 scores -Score.
+func FromTwoPlayer[State GameState](game Game[State]) MultiGame[State] {
+	return twoPlayerGame[State]{game}
+}
+
+// FromTwoPlayerMove is move, made by player in a game adapted by
+// FromTwoPlayer, as a MultiMove.
+func FromTwoPlayerMove[State GameState](move Move[State], player int) MultiMove[State] {
+	next := 1 - player
+	if move.RetainControl {
+		next = player
+	}
+	return MultiMove[State]{Summary: move.Summary, State: move.State, NextPlayer: next}
+}
+
+func (g twoPlayerGame[State]) Players() int {
+	return 2
+}
+
+func (g twoPlayerGame[State]) InitialState() State {
+	return g.game.InitialState()
+}
+
+func (g twoPlayerGame[State]) Describe(prospect MultiProspect[State]) MultiStateDescriptor[State] {
+	sd := g.game.Describe(Prospect[State]{State: prospect.State, FirstAgent: prospect.Player == 0})
+
+	out := MultiStateDescriptor[State]{Scores: []int{sd.Score, -sd.Score}}
+	for _, move := range sd.Moves {
+		out.Moves = append(out.Moves, FromTwoPlayerMove(move, prospect.Player))
+	}
+	for _, outcome := range sd.Outcomes {
+		out.Outcomes = append(out.Outcomes, MultiOutcome[State]{
+			Move:        FromTwoPlayerMove(outcome.Move, prospect.Player),
+			Probability: outcome.Probability,
+		})
+	}
+
+	return out
+}
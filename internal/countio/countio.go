@@ -0,0 +1,17 @@
+// Package countio counts the bytes written through an io.Writer, for WriteTo
+// methods whose encoders don't report how much they wrote.
+package countio
+
+import "io"
+
+// Writer passes writes on to W, adding up in N how many bytes W took.
+type Writer struct {
+	W io.Writer
+	N int64
+}
+
+func (cw *Writer) Write(p []byte) (int, error) {
+	n, err := cw.W.Write(p)
+	cw.N += int64(n)
+	return n, err
+}
@@ -34,6 +34,15 @@ func (s MancalaState) String() string {
 	return out
 }
 
+// Hash is an FNV-1a hash of the token counts in each pit and store.
+func (s MancalaState) Hash() uint64 {
+	tokens := make([]int, len(s))
+	for i, pit := range s {
+		tokens[i] = pit.tokens
+	}
+	return games.HashInts(tokens...)
+}
+
 type mancalaGame struct {
 	runLength  int
 	startCount int
@@ -74,6 +83,7 @@ func (g mancalaGame) InitialState() MancalaState {
 func mancalaMove(prospect games.Prospect[MancalaState], i int) games.Move[MancalaState] {
 	summary := fmt.Sprintf("pick up from %s", prospect.State[i].name)
 	retainControl := false
+	capture := false
 
 	pits := []mancalaPit{}
 	for _, pit := range prospect.State {
@@ -108,6 +118,7 @@ func mancalaMove(prospect games.Prospect[MancalaState], i int) games.Move[Mancal
 			pits[myStoreIndex].tokens += 1 + pits[oppositeIndex].tokens
 			pits[i].tokens = 0
 			pits[oppositeIndex].tokens = 0
+			capture = true
 		}
 	}
 
@@ -115,6 +126,7 @@ func mancalaMove(prospect games.Prospect[MancalaState], i int) games.Move[Mancal
 		State:         pits,
 		Summary:       summary,
 		RetainControl: retainControl,
+		Noisy:         capture,
 	}
 }
 
@@ -157,3 +169,74 @@ func (g mancalaGame) Describe(prospect games.Prospect[MancalaState]) games.State
 		Moves: moves,
 	}
 }
+
+const freeMoveWeight = games.EvaluationScale / 2
+
+// freeMoves counts the pits on the side starting at offset that hold exactly
+// enough seeds to end in that side's store, earning an extra turn.
+func freeMoves(s MancalaState, offset int) int {
+	runLength := len(s)/2 - 1
+	count := 0
+	for i := 0; i < runLength; i++ {
+		if s[i+offset].tokens == runLength-i {
+			count++
+		}
+	}
+	return count
+}
+
+type evaluator struct{}
+
+// Evaluator scores Mancala positions by the difference between the stores,
+// plus half a point for each pit that would earn the side to move an extra turn.
+func Evaluator() games.Evaluator[MancalaState] {
+	return evaluator{}
+}
+
+func (evaluator) Evaluate(prospect games.Prospect[MancalaState]) int {
+	s := prospect.State
+	runLength := len(s)/2 - 1
+
+	score := (s[runLength].tokens - s[2*runLength+1].tokens) * games.EvaluationScale
+
+	offset := 0
+	if !prospect.FirstAgent {
+		offset = runLength + 1
+	}
+	if prospect.FirstAgent {
+		score += freeMoveWeight * freeMoves(s, offset)
+	} else {
+		score -= freeMoveWeight * freeMoves(s, offset)
+	}
+
+	return score
+}
+
+// Featurizer describes Mancala positions for training an evaluator: a
+// constant, whose turn it is, and the differences between the players' stores,
+// the seeds on their sides of the board, and their pits that would earn an
+// extra turn.
+type Featurizer struct{}
+
+func (Featurizer) FeatureCount() int {
+	return 5
+}
+
+func (Featurizer) Features(prospect games.Prospect[MancalaState]) []float64 {
+	s := prospect.State
+	runLength := len(s)/2 - 1
+
+	turn := -1.0
+	if prospect.FirstAgent {
+		turn = 1
+	}
+
+	seeds := 0
+	for i := 0; i < runLength; i++ {
+		seeds += s[i].tokens - s[i+runLength+1].tokens
+	}
+	extraTurns := freeMoves(s, 0) - freeMoves(s, runLength+1)
+
+	stores := s[runLength].tokens - s[2*runLength+1].tokens
+	return []float64{1, turn, float64(stores) / 10, float64(seeds) / 10, float64(extraTurns) / 2}
+}
@@ -0,0 +1,322 @@
+package mancala
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+// unsolved marks tablebase entries that haven't been computed yet. No real
+// value can reach it, since MaxTablebaseSeeds keeps values within int8.
+const unsolved = math.MinInt8
+
+// MaxTablebaseSeeds is the most seeds a tablebase can cover.
+const MaxTablebaseSeeds = 127
+
+// readChunk is how many values ReadTablebase reads at a time.
+const readChunk = 1 << 20
+
+// maxTablebaseSize is the most positions a tablebase can hold, which keeps a
+// corrupt header from asking for more memory than any real tablebase needs.
+const maxTablebaseSize = 1 << 30
+
+// Tablebase holds the exact value of every position with up to MaxSeeds seeds
+// left in the pits: the difference between what the player to move and their
+// opponent will add to their stores from there on, with perfect play. Since
+// stores never affect play, that depends only on the pits and who is to move,
+// and positions are stored from the point of view of the player to move, with
+// their pits listed first.
+type Tablebase struct {
+	runLength int
+	maxSeeds  int
+	values    []int8
+
+	// binomial[n][k] is n choose k, and offsets[s] is the index of the first
+	// position with s seeds.
+	binomial [][]int
+	offsets  []int
+}
+
+// newTablebase sets up the indexing of a tablebase, without its values.
+func newTablebase(runLength int, maxSeeds int) (*Tablebase, error) {
+	if runLength < 1 || runLength > math.MaxUint8 {
+		return nil, fmt.Errorf("mancala: tablebase can cover 1 to %d pits a side, not %d", math.MaxUint8, runLength)
+	}
+	if maxSeeds < 0 || maxSeeds > MaxTablebaseSeeds {
+		return nil, fmt.Errorf("mancala: tablebase can cover 0 to %d seeds, not %d", MaxTablebaseSeeds, maxSeeds)
+	}
+	pits := 2 * runLength
+
+	binomial := make([][]int, maxSeeds+pits+1)
+	for n := range binomial {
+		binomial[n] = make([]int, pits+1)
+		binomial[n][0] = 1
+		for k := 1; k <= pits && k <= n; k++ {
+			// Binomials bigger than any tablebase can be are capped rather
+			// than left to overflow.
+			binomial[n][k] = binomial[n-1][k-1] + binomial[n-1][k]
+			if binomial[n][k] > maxTablebaseSize {
+				binomial[n][k] = maxTablebaseSize + 1
+			}
+		}
+	}
+
+	offsets := make([]int, maxSeeds+2)
+	for s := 0; s <= maxSeeds; s++ {
+		count := binomial[s+pits-1][pits-1]
+		if count > maxTablebaseSize-offsets[s] {
+			return nil, fmt.Errorf("mancala: a tablebase for %d pits a side and %d seeds would be too big", runLength, maxSeeds)
+		}
+		offsets[s+1] = offsets[s] + count
+	}
+
+	return &Tablebase{
+		runLength: runLength,
+		maxSeeds:  maxSeeds,
+		binomial:  binomial,
+		offsets:   offsets,
+	}, nil
+}
+
+// index ranks a distribution of seeds among the pits, mover's first, among all
+// the distributions with the same number of seeds.
+func (t *Tablebase) index(board []int, seeds int) int {
+	i := t.offsets[seeds]
+	left := seeds
+	for pit := 0; pit < len(board)-1; pit++ {
+		rest := len(board) - pit - 1
+		for v := 0; v < board[pit]; v++ {
+			i += t.binomial[left-v+rest-1][rest-1]
+		}
+		left -= board[pit]
+	}
+	return i
+}
+
+func (t *Tablebase) Len() int {
+	return len(t.values)
+}
+
+// BuildTablebase solves every Mancala position with runLength pits a side and
+// at most maxSeeds seeds in play. Every move either puts a seed in a store or
+// moves seeds toward the mover's store, so no position can repeat, and each is
+// solved once from the positions its moves lead to.
+func BuildTablebase(runLength int, maxSeeds int) (*Tablebase, error) {
+	t, err := newTablebase(runLength, maxSeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	t.values = make([]int8, t.offsets[maxSeeds+1])
+	for i := range t.values {
+		t.values[i] = unsolved
+	}
+
+	board := make([]int, 2*runLength)
+	var enumerate func(pit int, left int, seeds int)
+	enumerate = func(pit int, left int, seeds int) {
+		if pit == len(board)-1 {
+			board[pit] = left
+			t.solve(board, seeds)
+			return
+		}
+		for v := 0; v <= left; v++ {
+			board[pit] = v
+			enumerate(pit+1, left-v, seeds)
+		}
+	}
+	for seeds := 0; seeds <= maxSeeds; seeds++ {
+		enumerate(0, seeds, seeds)
+	}
+
+	return t, nil
+}
+
+// solve returns the value of board for the player whose pits come first,
+// computing it if need be.
+func (t *Tablebase) solve(board []int, seeds int) int {
+	i := t.index(board, seeds)
+	if t.values[i] != unsolved {
+		return int(t.values[i])
+	}
+
+	r := t.runLength
+	mine := 0
+	for _, tokens := range board[:r] {
+		mine += tokens
+	}
+
+	value := 0
+	if mine == 0 && seeds > 0 {
+		// The player to move has to pass.
+		value = -t.solve(swapSides(board), seeds)
+	} else if mine > 0 {
+		value = math.MinInt
+		for pit := 0; pit < r; pit++ {
+			if board[pit] == 0 {
+				continue
+			}
+
+			next, gain, again := sow(board, pit)
+			v := gain
+			if again {
+				v += t.solve(next, seeds-gain)
+			} else {
+				v -= t.solve(swapSides(next), seeds-gain)
+			}
+			if v > value {
+				value = v
+			}
+		}
+	}
+
+	t.values[i] = int8(value)
+	return value
+}
+
+// sow plays a move from pit on a board without stores, mover's pits first,
+// following the same rules as mancalaMove. It returns the pits afterwards,
+// how many seeds went into the mover's store, and whether they move again.
+func sow(board []int, pit int) ([]int, int, bool) {
+	r := len(board) / 2
+
+	// Lay the board out as mancalaMove does, with the mover's store at r and
+	// the opponent's at the end.
+	pits := make([]int, len(board)+2)
+	copy(pits[:r], board[:r])
+	copy(pits[r+1:], board[r:])
+
+	i := pit
+	hand := pits[i]
+	pits[i] = 0
+	for hand > 0 {
+		i = (i + 1) % len(pits)
+		if i == len(pits)-1 {
+			continue
+		}
+		pits[i]++
+		hand--
+	}
+
+	again := i == r
+	if !again && i < r && pits[i] == 1 {
+		opposite := len(pits) - i - 2
+		if pits[opposite] > 0 {
+			pits[r] += 1 + pits[opposite]
+			pits[i] = 0
+			pits[opposite] = 0
+		}
+	}
+
+	next := make([]int, len(board))
+	copy(next[:r], pits[:r])
+	copy(next[r:], pits[r+1:len(pits)-1])
+	return next, pits[r], again
+}
+
+func swapSides(board []int) []int {
+	r := len(board) / 2
+	out := make([]int, len(board))
+	copy(out[:r], board[r:])
+	copy(out[r:], board[:r])
+	return out
+}
+
+// Lookup returns the final Score of the game from prospect with perfect play,
+// from the first agent's point of view, if the position is in the tablebase.
+// It makes a Tablebase a games.Oracle.
+func (t *Tablebase) Lookup(prospect games.Prospect[MancalaState]) (int, bool) {
+	s := prospect.State
+	r := t.runLength
+	if len(s) != 2*r+2 {
+		return 0, false
+	}
+
+	board := make([]int, 0, 2*r)
+	seeds := 0
+	for i, pit := range s {
+		if i != r && i != 2*r+1 {
+			board = append(board, pit.tokens)
+			seeds += pit.tokens
+		}
+	}
+	if seeds > t.maxSeeds {
+		return 0, false
+	}
+	if !prospect.FirstAgent {
+		board = swapSides(board)
+	}
+
+	value := int(t.values[t.index(board, seeds)])
+	if value == unsolved {
+		return 0, false
+	}
+	if !prospect.FirstAgent {
+		value = -value
+	}
+	return s[r].tokens - s[2*r+1].tokens + value, true
+}
+
+var tablebaseMagic = [4]byte{'M', 'N', 'C', 'L'}
+
+type tablebaseHeader struct {
+	Magic     [4]byte
+	RunLength uint8
+	MaxSeeds  uint8
+}
+
+// WriteTo writes the tablebase as a short header followed by one byte per
+// position.
+func (t *Tablebase) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	header := tablebaseHeader{tablebaseMagic, uint8(t.runLength), uint8(t.maxSeeds)}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return n, err
+	}
+	n += int64(binary.Size(header))
+
+	err := binary.Write(w, binary.LittleEndian, t.values)
+	if err == nil {
+		n += int64(len(t.values))
+	}
+	return n, err
+}
+
+// ReadTablebase reads a tablebase written by Tablebase.WriteTo.
+func ReadTablebase(r io.Reader) (*Tablebase, error) {
+	var header tablebaseHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != tablebaseMagic {
+		return nil, fmt.Errorf("mancala: not a tablebase")
+	}
+
+	t, err := newTablebase(int(header.RunLength), int(header.MaxSeeds))
+	if err != nil {
+		return nil, err
+	}
+
+	// Values are read a chunk at a time, so that a file too short for its
+	// header fails before all the room the header asks for is made.
+	size := t.offsets[t.maxSeeds+1]
+	t.values = make([]int8, 0, readChunk)
+	for len(t.values) < size {
+		n := size - len(t.values)
+		if n > readChunk {
+			n = readChunk
+		}
+		chunk := make([]int8, n)
+		if err := binary.Read(r, binary.LittleEndian, chunk); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		t.values = append(t.values, chunk...)
+	}
+	return t, nil
+}
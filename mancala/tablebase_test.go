@@ -0,0 +1,88 @@
+package mancala_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/mancala"
+	"github.com/cstuartroe/minimax/minimaxer"
+)
+
+// TestTablebaseMatchesSearch plays random games down into the tablebase, and
+// checks the value it gives every position it covers against a search to the
+// end of the game.
+func TestTablebaseMatchesSearch(t *testing.T) {
+	const runLength, maxSeeds = 3, 8
+
+	tb, err := mancala.BuildTablebase(runLength, maxSeeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := mancala.MancalaGame(runLength, 3)
+	rng := rand.New(rand.NewSource(1))
+	checked := 0
+	for i := 0; i < 20; i++ {
+		prospect := games.Prospect[mancala.MancalaState]{State: game.InitialState(), FirstAgent: true}
+		for {
+			moves := game.Describe(prospect).Moves
+			if len(moves) == 0 {
+				break
+			}
+
+			if want, ok := tb.Lookup(prospect); ok {
+				best := -1 << 62
+				for _, rating := range minimaxer.NewMinimaxer(game, 100).WithAlphaBeta().RateChoices(prospect) {
+					if rating.Score > best {
+						best = rating.Score
+					}
+				}
+				if got := minimaxer.ToFirstAgent(best, prospect.FirstAgent); got != want*games.EvaluationScale {
+					t.Errorf("tablebase gives\n%s\nwith %t to move %d, search gives %s", prospect.State, prospect.FirstAgent, want, minimaxer.FormatScore(got))
+				}
+				checked++
+			}
+
+			prospect = prospect.After(moves[rng.Intn(len(moves))])
+		}
+	}
+
+	if checked < 50 {
+		t.Errorf("only %d positions were in the tablebase", checked)
+	}
+}
+
+func TestReadTablebase(t *testing.T) {
+	tb, err := mancala.BuildTablebase(2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := tb.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written := buf.Bytes()
+
+	read, err := mancala.ReadTablebase(bytes.NewReader(written))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Len() != tb.Len() {
+		t.Errorf("read %d positions, wrote %d", read.Len(), tb.Len())
+	}
+
+	corrupt := map[string][]byte{
+		"no pits":   append([]byte{'M', 'N', 'C', 'L', 0, 6}, written[6:]...),
+		"too big":   append([]byte{'M', 'N', 'C', 'L', 200, 127}, written[6:]...),
+		"too many":  append([]byte{'M', 'N', 'C', 'L', 2, 200}, written[6:]...),
+		"truncated": written[:len(written)-1],
+	}
+	for name, data := range corrupt {
+		if _, err := mancala.ReadTablebase(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: read a corrupt tablebase", name)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package mcts
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+const DefaultExploration = math.Sqrt2
+
+// MCTS is a player that chooses moves by Monte Carlo tree search with UCT:
+// it plays random games out from the current position and grows a tree of the
+// moves that led to the best results.
+type MCTS[State games.GameState] struct {
+	game        games.Game[State]
+	iterations  int
+	timeLimit   time.Duration
+	exploration float64
+	rng         *rand.Rand
+
+	lastIterations int
+	lastWinRate    float64
+}
+
+// NewMCTS returns an MCTS player that runs the given number of playouts per move.
+func NewMCTS[State games.GameState](game games.Game[State], iterations int) *MCTS[State] {
+	return &MCTS[State]{
+		game:        game,
+		iterations:  iterations,
+		exploration: DefaultExploration,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NewTimedMCTS returns an MCTS player that runs playouts until timeLimit has
+// passed on each move.
+func NewTimedMCTS[State games.GameState](game games.Game[State], timeLimit time.Duration) *MCTS[State] {
+	return NewMCTS(game, 0).WithTimeLimit(timeLimit)
+}
+
+// WithTimeLimit stops each search after timeLimit. A nonzero iteration count
+// still caps the number of playouts.
+func (p *MCTS[State]) WithTimeLimit(timeLimit time.Duration) *MCTS[State] {
+	p.timeLimit = timeLimit
+	return p
+}
+
+// WithExploration sets the UCT exploration constant. Higher values spread
+// playouts more evenly across moves; lower values focus on the best so far.
+func (p *MCTS[State]) WithExploration(exploration float64) *MCTS[State] {
+	p.exploration = exploration
+	return p
+}
+
+// WithSeed makes the playouts, and every other random choice, come from a
+// source seeded with seed. Without a time limit, the same seed and the same
+// positions then give the same moves.
+func (p *MCTS[State]) WithSeed(seed int64) *MCTS[State] {
+	return p.WithRandSource(rand.NewSource(seed))
+}
+
+// WithRandSource makes every random choice come from src.
+func (p *MCTS[State]) WithRandSource(src rand.Source) *MCTS[State] {
+	p.rng = rand.New(src)
+	return p
+}
+
+func (p *MCTS[State]) Name() string {
+	return fmt.Sprintf("MCTS @%p", p)
+}
+
+type node[State games.GameState] struct {
+	prospect games.Prospect[State]
+	score    int
+	moves    []games.Move[State]
+	outcomes []games.Outcome[State]
+	children []*node[State]
+	untried  []int
+
+	visits float64
+	// reward totals the results of playouts through this node, from the
+	// point of view of the agent who made the move leading here.
+	reward float64
+}
+
+func (p *MCTS[State]) newNode(prospect games.Prospect[State]) *node[State] {
+	sd := p.game.Describe(prospect)
+	return &node[State]{
+		prospect: prospect,
+		score:    sd.Score,
+		moves:    sd.Moves,
+		outcomes: sd.Outcomes,
+		children: make([]*node[State], len(sd.Moves)+len(sd.Outcomes)),
+		untried:  p.rng.Perm(len(sd.Moves)),
+	}
+}
+
+// result converts a final score to a reward for the first agent: 1 for a win,
+// 0 for a loss, and a half for a draw.
+func result(score int) float64 {
+	if score > 0 {
+		return 1
+	} else if score < 0 {
+		return 0
+	}
+	return 0.5
+}
+
+func (p *MCTS[State]) uct(parent *node[State], child *node[State]) float64 {
+	return child.reward/child.visits + p.exploration*math.Sqrt(math.Log(parent.visits)/child.visits)
+}
+
+func (p *MCTS[State]) selectChild(n *node[State]) *node[State] {
+	var best *node[State]
+	bestValue := math.Inf(-1)
+	for _, child := range n.children {
+		if value := p.uct(n, child); value > bestValue {
+			best, bestValue = child, value
+		}
+	}
+	return best
+}
+
+func (p *MCTS[State]) playout(prospect games.Prospect[State]) float64 {
+	for {
+		sd := p.game.Describe(prospect)
+		if len(sd.Outcomes) > 0 {
+			outcome := sd.Outcomes[games.PickOutcome(sd.Outcomes, p.rng.Float64())]
+			prospect = prospect.After(outcome.Move)
+			continue
+		}
+		if len(sd.Moves) == 0 {
+			return result(sd.Score)
+		}
+		prospect = prospect.After(sd.Moves[p.rng.Intn(len(sd.Moves))])
+	}
+}
+
+// iterate walks down the tree by UCT, sampling the outcome at chance nodes,
+// until it reaches a move that hasn't been tried or the end of the game. It
+// then plays the game out at random and records the result along the path.
+func (p *MCTS[State]) iterate(root *node[State]) {
+	path := []*node[State]{root}
+	n := root
+
+	var r float64
+	for {
+		if len(n.outcomes) > 0 {
+			i := games.PickOutcome(n.outcomes, p.rng.Float64())
+			if n.children[i] == nil {
+				n.children[i] = p.newNode(n.prospect.After(n.outcomes[i].Move))
+				path = append(path, n.children[i])
+				r = p.playout(n.children[i].prospect)
+				break
+			}
+			n = n.children[i]
+		} else if len(n.untried) > 0 {
+			i := n.untried[len(n.untried)-1]
+			n.untried = n.untried[:len(n.untried)-1]
+
+			n.children[i] = p.newNode(n.prospect.After(n.moves[i]))
+			path = append(path, n.children[i])
+			r = p.playout(n.children[i].prospect)
+			break
+		} else if len(n.moves) == 0 {
+			r = result(n.score)
+			break
+		} else {
+			n = p.selectChild(n)
+		}
+		path = append(path, n)
+	}
+
+	for i, visited := range path {
+		visited.visits++
+		if i == 0 {
+			continue
+		}
+		if path[i-1].prospect.FirstAgent {
+			visited.reward += r
+		} else {
+			visited.reward += 1 - r
+		}
+	}
+}
+
+func (p *MCTS[State]) keepGoing(iterations int, deadline time.Time) bool {
+	if p.iterations > 0 && iterations >= p.iterations {
+		return false
+	}
+	if p.timeLimit > 0 && !time.Now().Before(deadline) {
+		return false
+	}
+	return p.iterations > 0 || p.timeLimit > 0
+}
+
+func (p *MCTS[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
+	root := p.newNode(prospect)
+
+	deadline := time.Now().Add(p.timeLimit)
+	iterations := 0
+	for iterations == 0 || p.keepGoing(iterations, deadline) {
+		p.iterate(root)
+		iterations++
+	}
+
+	best := -1
+	for i, child := range root.children {
+		if child != nil && (best == -1 || child.visits > root.children[best].visits) {
+			best = i
+		}
+	}
+
+	p.lastIterations = iterations
+	p.lastWinRate = root.children[best].reward / root.children[best].visits
+
+	return root.moves[best]
+}
+
+func (p *MCTS[State]) Comment() string {
+	return fmt.Sprintf("I ran %d playouts, and my move won %.0f%% of its own.", p.lastIterations, 100*p.lastWinRate)
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"time"
+
 	"github.com/cstuartroe/minimax/connect_four"
 	"github.com/cstuartroe/minimax/gameplay"
 	"github.com/cstuartroe/minimax/games"
@@ -12,16 +16,20 @@ func p[State games.GameState](game games.Game[State], player gameplay.Player[Sta
 }
 
 func main() {
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for every random choice; reuse one to replay a game")
+	flag.Parse()
+	fmt.Printf("Seed: %d\n", *seed)
+
 	game := connect_four.ConnectFour()
 
-	mx1 := minimaxer.NewMinimaxer(game, 10)
-	mx2 := minimaxer.NewMinimaxer(game, 10)
+	mx1 := minimaxer.NewMinimaxer(game, 10).WithAlphaBeta().WithParallelSearch().WithEvaluator(connect_four.Evaluator()).WithSeed(*seed)
+	mx2 := minimaxer.NewMinimaxer(game, 10).WithAlphaBeta().WithParallelSearch().WithEvaluator(connect_four.Evaluator()).WithSeed(*seed + 1)
 	// me := gameplay.NewHumanPlayer("Conor", game)
 
 	var player1 gameplay.Player[connect_four.ConnectFourState] = mx1
 	var player2 gameplay.Player[connect_four.ConnectFourState] = mx2
 
-	gp := gameplay.NewGameplay(game, player1, player2)
+	gp := gameplay.NewGameplay(game, player1, player2).WithSeed(*seed)
 
 	gp.Play(true)
 }
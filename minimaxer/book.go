@@ -0,0 +1,120 @@
+package minimaxer
+
+import (
+	"encoding/gob"
+	"io"
+	"math/rand"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/internal/countio"
+)
+
+// BookMove is one move a Book recommends, by Summary, and its relative weight.
+type BookMove struct {
+	Summary string
+	Weight  int
+}
+
+// Book is an opening book: for positions near the start of a game, keyed by
+// games.Prospect.Hash, the moves worth playing and how often to play each.
+type Book struct {
+	Positions map[uint64][]BookMove
+}
+
+// BuildBook analyzes every position within plies moves of the game's initial
+// state with m.RateChoices. Each position's book moves are the ones scoring
+// within tolerance of the best, weighted by how close they come to it.
+func BuildBook[State games.GameState](game games.Game[State], m *Minimaxer[State], plies int, tolerance int) *Book {
+	book := &Book{Positions: map[uint64][]BookMove{}}
+
+	var build func(prospect games.Prospect[State], ply int)
+	build = func(prospect games.Prospect[State], ply int) {
+		key := prospect.Hash()
+		if _, ok := book.Positions[key]; ok || ply >= plies {
+			return
+		}
+
+		sd := game.Describe(prospect)
+		if len(sd.Moves) == 0 {
+			return
+		}
+
+		ratings := m.RateChoices(prospect)
+		best := -infinity
+		for _, rating := range ratings {
+			if rating.searchScore > best {
+				best = rating.searchScore
+			}
+		}
+
+		moves := []BookMove{}
+		for _, rating := range ratings {
+			shortfall := best - rating.searchScore
+			if shortfall <= tolerance*distanceScale {
+				moves = append(moves, BookMove{
+					Summary: rating.Move.Summary,
+					Weight:  tolerance - shortfall/distanceScale + 1,
+				})
+			}
+		}
+		book.Positions[key] = moves
+
+		for _, move := range sd.Moves {
+			build(prospect.After(move), ply+1)
+		}
+	}
+
+	build(games.Prospect[State]{State: game.InitialState(), FirstAgent: true}, 0)
+	return book
+}
+
+// WriteTo writes the book in gob encoding.
+func (b *Book) WriteTo(w io.Writer) (int64, error) {
+	cw := &countio.Writer{W: w}
+	err := gob.NewEncoder(cw).Encode(b)
+	return cw.N, err
+}
+
+// ReadBook reads a book written by Book.WriteTo.
+func ReadBook(r io.Reader) (*Book, error) {
+	b := &Book{}
+	if err := gob.NewDecoder(r).Decode(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// bookMove picks one of the book's moves for prospect at random, in
+// proportion to their weights, if the book has any that are legal there.
+func bookMove[State games.GameState](b *Book, prospect games.Prospect[State], moves []games.Move[State], rng *rand.Rand) (games.Move[State], bool) {
+	bookMoves, ok := b.Positions[prospect.Hash()]
+	if !ok {
+		return games.Move[State]{}, false
+	}
+
+	legal := []games.Move[State]{}
+	weights := []int{}
+	total := 0
+	for _, bm := range bookMoves {
+		for _, move := range moves {
+			if move.Summary == bm.Summary && bm.Weight > 0 {
+				legal = append(legal, move)
+				weights = append(weights, bm.Weight)
+				total += bm.Weight
+				break
+			}
+		}
+	}
+	if total == 0 {
+		return games.Move[State]{}, false
+	}
+
+	x := rng.Intn(total)
+	for i, weight := range weights {
+		x -= weight
+		if x < 0 {
+			return legal[i], true
+		}
+	}
+	return legal[len(legal)-1], true
+}
@@ -1,104 +1,517 @@
 package minimaxer
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cstuartroe/minimax/gameplay"
 	"github.com/cstuartroe/minimax/games"
 )
 
 type Minimaxer[State games.GameState] struct {
-	game           games.Game[State]
-	prospectScores map[string]int
-	lookahead      int
+	game        games.Game[State]
+	prioritizer games.MovePrioritizer[State]
+	symmetric   games.Symmetric[State]
+	table       *table[struct{}]
+	tableSize   int
+	persistent  bool
+	evaluator   games.Evaluator[State]
+	oracle      games.Oracle[State]
+	ordering    MoveOrdering
+	book        *Book
+	lookahead   int
+	quiescence  int
+	maxChain    int
+	alphaBeta   bool
+	timeLimit   time.Duration
+	workers     int
+	rng         *rand.Rand
+
+	searchers  []*searcher[State]
+	ctx        context.Context
+	deadline   time.Time
+	stopped    atomic.Bool
+	stats      Stats
+	hitHorizon bool
+	fromBook   bool
 }
 
 func NewMinimaxer[State games.GameState](game games.Game[State], lookahead int) *Minimaxer[State] {
+	prioritizer, _ := game.(games.MovePrioritizer[State])
+	symmetric, _ := game.(games.Symmetric[State])
 	return &Minimaxer[State]{
-		game:      game,
-		lookahead: lookahead,
+		game:        game,
+		tableSize:   defaultTableSize,
+		ordering:    DefaultOrdering,
+		prioritizer: prioritizer,
+		symmetric:   symmetric,
+		lookahead:   lookahead,
+		workers:     1,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
+// NewTimedMinimaxer returns a Minimaxer that deepens its search one ply at a
+// time until timeLimit has passed, then plays the best move from the deepest
+// search it finished.
+func NewTimedMinimaxer[State games.GameState](game games.Game[State], timeLimit time.Duration) *Minimaxer[State] {
+	return NewMinimaxer(game, 0).WithTimeLimit(timeLimit)
+}
+
+// WithTimeLimit makes the search iteratively deepen until timeLimit has
+// passed. A nonzero lookahead still caps the depth.
+func (m *Minimaxer[State]) WithTimeLimit(timeLimit time.Duration) *Minimaxer[State] {
+	m.timeLimit = timeLimit
+	return m
+}
+
+// WithSeed makes every random choice, like which of several equally good
+// moves to play, come from a source seeded with seed. Given the same seed and
+// the same positions, the Minimaxer plays the same moves, unless a time limit
+// cuts its searches short at different points.
+func (m *Minimaxer[State]) WithSeed(seed int64) *Minimaxer[State] {
+	return m.WithRandSource(rand.NewSource(seed))
+}
+
+// WithRandSource makes every random choice come from src.
+func (m *Minimaxer[State]) WithRandSource(src rand.Source) *Minimaxer[State] {
+	m.rng = rand.New(src)
+	return m
+}
+
+// WithQuiescence keeps searching past the lookahead, for up to plies more
+// moves, as long as there are noisy moves to play: ones marked games.Move.Noisy,
+// or that keep control of the turn. Past the lookahead, the player to move may
+// also stop and take the evaluation of the position as it stands, so only the
+// noisy moves that improve on it count.
+func (m *Minimaxer[State]) WithQuiescence(plies int) *Minimaxer[State] {
+	m.quiescence = plies
+	return m
+}
+
+// WithTurnDepth counts the lookahead in turns changing hands rather than in
+// moves: a move that keeps control of the turn doesn't use up any depth, so
+// long chains of extra turns don't cut short the search of the replies to
+// them. Only the first maxChain moves of a chain are free, so that a game
+// where one player can keep the turn indefinitely can't stall the search.
+func (m *Minimaxer[State]) WithTurnDepth(maxChain int) *Minimaxer[State] {
+	m.maxChain = maxChain
+	return m
+}
+
+// WithAlphaBeta switches the search to alpha-beta pruning. Move scores are the
+// same as with the full search, but branches that can't affect them are skipped.
+func (m *Minimaxer[State]) WithAlphaBeta() *Minimaxer[State] {
+	m.alphaBeta = true
+	return m
+}
+
+// WithEvaluator scores positions at the lookahead limit with evaluator
+// instead of with the game's Score.
+func (m *Minimaxer[State]) WithEvaluator(evaluator games.Evaluator[State]) *Minimaxer[State] {
+	m.evaluator = evaluator
+	return m
+}
+
+// WithPersistentTable keeps the table from one search to the next, rather than
+// starting each move with an empty one. Scores are only reused at the depth
+// they were searched to, which a later search rarely reaches the same position
+// with, so what carries over is mostly each position's best move, tried first
+// when the position comes up again. That only saves time with WithAlphaBeta,
+// where better move ordering lets more of the tree be pruned. Entries from
+// earlier searches are the first to be replaced once the table fills up.
+func (m *Minimaxer[State]) WithPersistentTable() *Minimaxer[State] {
+	m.persistent = true
+	return m
+}
+
+// WithBook makes ChooseMove play from the opening book, without searching,
+// whenever the book has moves for the position.
+func (m *Minimaxer[State]) WithBook(book *Book) *Minimaxer[State] {
+	m.book = book
+	return m
+}
+
+// WithOracle has the search take the scores of the positions oracle knows
+// from it, as finished games, instead of searching them.
+func (m *Minimaxer[State]) WithOracle(oracle games.Oracle[State]) *Minimaxer[State] {
+	m.oracle = oracle
+	return m
+}
+
+// WithMoveOrdering chooses the heuristics used to order moves during search.
+func (m *Minimaxer[State]) WithMoveOrdering(ordering MoveOrdering) *Minimaxer[State] {
+	m.ordering = ordering
+	return m
+}
+
+// WithParallelSearch spreads each search across one goroutine per CPU core.
+func (m *Minimaxer[State]) WithParallelSearch() *Minimaxer[State] {
+	return m.WithWorkers(runtime.NumCPU())
+}
+
+// WithWorkers spreads each search across the given number of goroutines. The
+// moves at the root are divided between them, and they share one table, so
+// scores come out the same as from a single-threaded search.
+func (m *Minimaxer[State]) WithWorkers(workers int) *Minimaxer[State] {
+	if workers < 1 {
+		workers = 1
+	}
+	m.workers = workers
+	return m
+}
+
+// WithTableSize caps how many scored positions the search remembers. Once the
+// table is full, shallower results are evicted in favor of deeper ones.
+func (m *Minimaxer[State]) WithTableSize(entries int) *Minimaxer[State] {
+	m.tableSize = entries
+	return m
+}
+
 func (m *Minimaxer[State]) Name() string {
 	return fmt.Sprintf("Minimaxer @%p", m)
 }
 
-func (m Minimaxer[State]) Size() int {
-	return len(m.prospectScores)
+func (m *Minimaxer[State]) Size() int {
+	if m.table == nil {
+		return 0
+	}
+	return int(m.table.size.Load())
 }
 
 func (m *Minimaxer[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
-	m.prospectScores = map[string]int{}
-	_, move := m.chooseMove(prospect, m.lookahead)
-	return *move
+	move, _ := m.ChooseMoveContext(context.Background(), prospect)
+	return move
+}
+
+// ChooseMoveContext is like ChooseMove, but stops searching when ctx is done.
+// The search then deepens one ply at a time, so that once cancelled it can
+// return the best move from the deepest search it finished. If it hadn't
+// finished any, it returns ctx's error.
+func (m *Minimaxer[State]) ChooseMoveContext(ctx context.Context, prospect games.Prospect[State]) (games.Move[State], error) {
+	m.fromBook = false
+	if m.book != nil {
+		if move, ok := bookMove(m.book, prospect, m.game.Describe(prospect).Moves, m.rng); ok {
+			m.fromBook = true
+			m.stats = Stats{}
+			return move, nil
+		}
+	}
+
+	var goodMoves []games.Move[State]
+	err := m.deepen(ctx, func(searchDepth int) {
+		moves := m.bestMoves(prospect, searchDepth)
+		if !m.stopped.Load() {
+			goodMoves = moves
+		}
+	})
+	if err != nil {
+		return games.Move[State]{}, err
+	}
+	return goodMoves[m.rng.Intn(len(goodMoves))], nil
 }
 
+// deepen calls try once at the lookahead, or, with a time limit or a context
+// that can be cancelled, at depths 1, 2, 3... until the search is stopped,
+// the lookahead is reached, or a search saw the whole game tree. The time
+// limit never cuts the first depth short, but cancelling ctx does, in which
+// case deepen returns ctx's error.
+func (m *Minimaxer[State]) deepen(ctx context.Context, try func(searchDepth int)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if m.table == nil || !m.persistent {
+		m.table = newTable[struct{}](m.tableSize)
+	}
+	m.table.newSearch()
+	m.searchers = make([]*searcher[State], m.workers)
+	for w := range m.searchers {
+		m.searchers[w] = &searcher[State]{m: m}
+	}
+	m.ctx = ctx
+	m.deadline = time.Time{}
+	m.stopped.Store(false)
+	m.stats = Stats{}
+
+	start := time.Now()
+	defer func() { m.stats.Elapsed = time.Since(start) }()
+
+	// timed runs try at searchDepth, and records its timing if it finished.
+	timed := func(searchDepth int) {
+		depthStart := time.Now()
+		try(searchDepth)
+		if !m.stopped.Load() {
+			m.stats.Depth = searchDepth
+			m.stats.DepthTimes = append(m.stats.DepthTimes, time.Since(depthStart))
+		}
+	}
+
+	if m.timeLimit == 0 && (ctx.Done() == nil || m.lookahead <= 0) {
+		timed(m.lookahead)
+		return nil
+	}
+
+	for searchDepth := 1; ; searchDepth++ {
+		m.hitHorizon = false
+		timed(searchDepth)
+
+		if m.stopped.Load() {
+			if searchDepth == 1 {
+				return ctx.Err()
+			}
+			return nil
+		}
+		if !m.hitHorizon || searchDepth == m.lookahead {
+			return nil
+		}
+		if m.timeLimit > 0 {
+			m.deadline = start.Add(m.timeLimit)
+		}
+	}
+}
+
+// Stats returns statistics about the most recent search.
+func (m *Minimaxer[State]) Stats() Stats {
+	return m.stats
+}
+
+// ReportStats describes the most recent search, for gameplay.StatsReporter.
+func (m *Minimaxer[State]) ReportStats() string {
+	if m.fromBook {
+		return ""
+	}
+	return m.stats.String()
+}
+
+// parallel calls work(s, n) for every n in [0, count), spread across the
+// Minimaxer's workers, each with a searcher of its own.
+func (m *Minimaxer[State]) parallel(count int, work func(s *searcher[State], n int)) {
+	searchers := m.searchers
+	if len(searchers) > count {
+		searchers = searchers[:count]
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+
+	for _, s := range searchers {
+		s := s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := int(next.Add(1)) - 1; n < count; n = int(next.Add(1)) - 1 {
+				work(s, n)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range searchers {
+		m.stats.add(s.stats)
+		m.hitHorizon = m.hitHorizon || s.hitHorizon
+		s.stats = Stats{}
+		s.hitHorizon = false
+	}
+}
+
+// RatedMove is a move and its score from the point of view of the player
+// making it, in units of 1/games.EvaluationScale of a point; ToFirstAgent
+// converts it to the first agent's point of view. If the search found that the
+// move leads to a forced win or loss, Decided is true and Distance is how many
+// moves away, counting this one, the game ends with best play.
+// PrincipalVariation lists the summaries of the moves the search expects both
+// sides to play, starting with this one.
 type RatedMove[State games.GameState] struct {
-	Move  games.Move[State]
-	Score int
+	Move               games.Move[State]
+	Score              int
+	Decided            bool
+	Distance           int
+	PrincipalVariation []string
+
+	searchScore int
+}
+
+func newRatedMove[State games.GameState](move games.Move[State], searchScore int) RatedMove[State] {
+	value, _ := splitScore(searchScore)
+	distance, decided := scoreDistance(searchScore)
+	return RatedMove[State]{
+		Move:        move,
+		Score:       value,
+		Decided:     decided,
+		Distance:    distance,
+		searchScore: searchScore,
+	}
+}
+
+// ScoreString describes the score in points, and for a forced win or loss,
+// how soon the game ends.
+func (r RatedMove[State]) ScoreString() string {
+	if r.Decided {
+		return fmt.Sprintf("%s, ending in %d", FormatScore(r.Score), r.Distance)
+	}
+	return FormatScore(r.Score)
 }
 
 func (m *Minimaxer[State]) RateChoices(prospect games.Prospect[State]) []RatedMove[State] {
-	m.prospectScores = map[string]int{}
-	out := []RatedMove[State]{}
+	out, _ := m.RateChoicesContext(context.Background(), prospect)
+	return out
+}
 
-	for _, move := range m.game.Describe(prospect).Moves {
-		out = append(out, RatedMove[State]{
-			Score: m.getProspectScore(moveToProspect(move, prospect.FirstAgent), m.lookahead),
-			Move:  move,
+// RateChoicesContext is like RateChoices, but stops searching when ctx is
+// done, the same way as ChooseMoveContext.
+func (m *Minimaxer[State]) RateChoicesContext(ctx context.Context, prospect games.Prospect[State]) ([]RatedMove[State], error) {
+	var out []RatedMove[State]
+
+	err := m.deepen(ctx, func(searchDepth int) {
+		moves := m.game.Describe(prospect).Moves
+		ratings := make([]RatedMove[State], len(moves))
+		m.parallel(len(moves), func(s *searcher[State], n int) {
+			score := s.childScore(prospect, moves[n], 1, searchDepth, -infinity, infinity)
+			ratings[n] = newRatedMove(moves[n], score)
+			ratings[n].PrincipalVariation = append([]string{moves[n].Summary}, s.principalVariation(1)...)
 		})
+		if !m.stopped.Load() {
+			out = ratings
+		}
+	})
+
+	return out, err
+}
+
+func (m *Minimaxer[State]) Comment() string {
+	if m.fromBook {
+		return "I know this one from my opening book."
 	}
+	return fmt.Sprintf("I analyzed %d game states!", m.Size())
+}
 
-	return out
+// tableKey returns the key a prospect is stored under in the table, which is
+// shared by all its variants if the game is games.Symmetric, along with the
+// prospect's own hash.
+func (m *Minimaxer[State]) tableKey(prospect games.Prospect[State]) (uint64, uint64) {
+	hash := prospect.Hash()
+	if m.symmetric == nil {
+		return hash, hash
+	}
+
+	canonical := games.Prospect[State]{State: m.symmetric.Canonical(prospect.State), FirstAgent: prospect.FirstAgent}
+	return canonical.Hash(), hash
 }
 
-func (m Minimaxer[State]) Comment() string {
-	return fmt.Sprintf("I analyzed %d game states!", len(m.prospectScores))
+// tableMove returns the index among moves, the moves of prospect, of the best
+// move recorded in entry, or -1 if there isn't one. If entry was stored for
+// another variant of prospect, that's the move leading to the same position,
+// up to symmetry, as the recorded one.
+func (m *Minimaxer[State]) tableMove(entry tableEntry[struct{}], prospect games.Prospect[State], hash uint64, moves []games.Move[State]) int {
+	if entry.move < 0 || entry.move >= len(moves) {
+		return -1
+	}
+	if entry.hash == hash {
+		return entry.move
+	}
+	if m.symmetric == nil {
+		return -1
+	}
+
+	for i, move := range moves {
+		if key, _ := m.tableKey(prospect.After(move)); key == entry.moveKey {
+			return i
+		}
+	}
+	return -1
 }
 
-func moveToProspect[State games.GameState](move games.Move[State], firstAgent bool) games.Prospect[State] {
-	if !move.RetainControl {
-		firstAgent = !firstAgent
+const infinity = math.MaxInt
+
+// evaluate scores a position at the lookahead limit.
+func (m *Minimaxer[State]) evaluate(prospect games.Prospect[State], sd games.StateDescriptor[State]) int {
+	if m.evaluator != nil {
+		return m.evaluator.Evaluate(prospect)
 	}
+	return sd.Score * games.EvaluationScale
+}
 
-	return games.Prospect[State]{State: move.State, FirstAgent: firstAgent}
+// FormatScore writes a search score in points.
+func FormatScore(score int) string {
+	return fmt.Sprintf("%.3f", float64(score)/games.EvaluationScale)
 }
 
-func (m *Minimaxer[State]) chooseMove(prospect games.Prospect[State], searchDepth int) (int, *games.Move[State]) {
+// bestMoves returns every root move that shares the best score. The first
+// move is searched on its own to get a score to compare against. With pruning
+// enabled, the rest are searched with a window just wide enough to tell
+// whether they at least tie the best score found so far, so ties are exact.
+func (m *Minimaxer[State]) bestMoves(prospect games.Prospect[State], searchDepth int) []games.Move[State] {
+	key, hash := m.tableKey(prospect)
 	sd := m.game.Describe(prospect)
+	order := m.moveOrder(prospect, sd.Moves, key, hash, 0, nil)
+
+	scores := make([]int, len(order))
+	var score int
+	var mu sync.Mutex
 
-	if len(sd.Moves) == 0 || searchDepth == 0 {
-		return sd.Score, nil
+	m.parallel(1, func(s *searcher[State], _ int) {
+		scores[0] = s.childScore(prospect, sd.Moves[order[0]], 1, searchDepth-1, -infinity, infinity)
+		score = scores[0]
+	})
+
+	m.parallel(len(order)-1, func(s *searcher[State], n int) {
+		n++
+
+		alpha := -infinity
+		if m.alphaBeta {
+			mu.Lock()
+			alpha = score - 1
+			mu.Unlock()
+		}
+
+		ps := s.childScore(prospect, sd.Moves[order[n]], 1, searchDepth-1, alpha, infinity)
+
+		mu.Lock()
+		scores[n] = ps
+		if ps > score {
+			score = ps
+		}
+		mu.Unlock()
+	})
+
+	// The good moves are listed in the game's order rather than the search's,
+	// which depends on what's in the table, so that seeded choices among them
+	// are repeatable.
+	best := -1
+	tied := make([]bool, len(sd.Moves))
+	for n, i := range order {
+		if scores[n] == score {
+			if best == -1 {
+				best = i
+			}
+			tied[i] = true
+		}
 	}
 
-	score := 0
 	var goodMoves []games.Move[State]
-
 	for i, move := range sd.Moves {
-		ps := m.getProspectScore(moveToProspect(move, prospect.FirstAgent), searchDepth-1)
-		if (i == 0) || (ps > score && prospect.FirstAgent) || (ps < score && !prospect.FirstAgent) {
-			score = ps
-			goodMoves = []games.Move[State]{move}
-		} else if ps == score {
+		if tied[i] {
 			goodMoves = append(goodMoves, move)
 		}
 	}
 
-	chosenMove := goodMoves[rand.Intn(len(goodMoves))]
-
-	return score, &chosenMove
-}
-
-func (m *Minimaxer[State]) getProspectScore(prospect games.Prospect[State], searchDepth int) int {
-	scoreString := prospect.String()
-
-	if _, ok := m.prospectScores[scoreString]; !ok {
-		m.prospectScores[scoreString], _ = m.chooseMove(prospect, searchDepth)
+	if !m.stopped.Load() {
+		var bestKey uint64
+		if m.symmetric != nil {
+			bestKey, _ = m.tableKey(prospect.After(sd.Moves[best]))
+		}
+		m.table.store(tableEntry[struct{}]{key: key, hash: hash, score: score, depth: searchDepth, bound: exactBound, move: best, moveKey: bestKey})
 	}
 
-	return m.prospectScores[scoreString]
+	return goodMoves
 }
 
 type AssistedHumanPlayer[State games.GameState] struct {
@@ -117,16 +530,13 @@ func (p AssistedHumanPlayer[State]) Name() string {
 func (p AssistedHumanPlayer[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
 	move := p.human.ChooseMove(prospect)
 
-	bestScore := -100000
+	bestScore := -infinity
 	bestMoves := []games.Move[State]{}
 
 	fmt.Println("Good thought! Here's how the minimaxer rates the moves:")
 	for _, ratedMove := range p.minimaxer.RateChoices(prospect) {
-		fmt.Printf("%s: %d\n", ratedMove.Move.Summary, ratedMove.Score)
-		score := ratedMove.Score
-		if !prospect.FirstAgent {
-			score = -ratedMove.Score
-		}
+		fmt.Printf("%s: %s (expecting %s)\n", ratedMove.Move.Summary, ratedMove.ScoreString(), strings.Join(ratedMove.PrincipalVariation, ", "))
+		score := ratedMove.searchScore
 		if score > bestScore {
 			bestScore = score
 			bestMoves = []games.Move[State]{ratedMove.Move}
@@ -0,0 +1,213 @@
+package minimaxer_test
+
+import (
+	"testing"
+
+	"github.com/cstuartroe/minimax/connect_four"
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/mancala"
+	"github.com/cstuartroe/minimax/minimaxer"
+	"github.com/cstuartroe/minimax/nim"
+	"github.com/cstuartroe/minimax/peg_solitaire"
+	"github.com/cstuartroe/minimax/tictactoe"
+)
+
+// mode picks the search options of a Minimaxer under test.
+type mode struct {
+	alphaBeta bool
+	workers   int
+	turnDepth int
+	tableSize int
+}
+
+func newMinimaxer[State games.GameState](game games.Game[State], lookahead int, evaluator games.Evaluator[State], md mode) *minimaxer.Minimaxer[State] {
+	m := minimaxer.NewMinimaxer(game, lookahead)
+	if evaluator != nil {
+		m = m.WithEvaluator(evaluator)
+	}
+	if md.alphaBeta {
+		m = m.WithAlphaBeta()
+	}
+	if md.workers > 0 {
+		m = m.WithWorkers(md.workers)
+	}
+	if md.turnDepth > 0 {
+		m = m.WithTurnDepth(md.turnDepth)
+	}
+	if md.tableSize > 0 {
+		m = m.WithTableSize(md.tableSize)
+	}
+	return m
+}
+
+// line plays plies moves from game's initial state, picking them by a fixed
+// rule, and returns the positions along the way.
+func line[State games.GameState](game games.Game[State], plies int) []games.Prospect[State] {
+	prospects := []games.Prospect[State]{{State: game.InitialState(), FirstAgent: true}}
+	for ply := 0; ply < plies; ply++ {
+		prospect := prospects[len(prospects)-1]
+		moves := game.Describe(prospect).Moves
+		if len(moves) == 0 {
+			break
+		}
+		prospects = append(prospects, prospect.After(moves[(3*ply+1)%len(moves)]))
+	}
+	return prospects
+}
+
+// expectSameRatings rates the moves from each position along a line of plies
+// moves with a Minimaxer in each mode, and fails if any move's score differs
+// between them.
+func expectSameRatings[State games.GameState](t *testing.T, game games.Game[State], plies int, lookahead int, evaluator games.Evaluator[State], base mode, variant mode) {
+	t.Helper()
+
+	for ply, prospect := range line(game, plies) {
+		if len(game.Describe(prospect).Moves) == 0 {
+			continue
+		}
+		want := newMinimaxer(game, lookahead, evaluator, base).RateChoices(prospect)
+		got := newMinimaxer(game, lookahead, evaluator, variant).RateChoices(prospect)
+
+		if len(got) != len(want) {
+			t.Fatalf("ply %d: got %d rated moves, want %d", ply, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Move.Summary != want[i].Move.Summary {
+				t.Fatalf("ply %d: move %d is %q, want %q", ply, i, got[i].Move.Summary, want[i].Move.Summary)
+			}
+			if got[i].Score != want[i].Score || got[i].Decided != want[i].Decided || got[i].Distance != want[i].Distance {
+				t.Errorf("ply %d, %+v: %s: got %s, want %s", ply, variant, want[i].Move.Summary, got[i].ScoreString(), want[i].ScoreString())
+			}
+		}
+	}
+}
+
+// gameCases are small games to compare search modes on, from a few positions
+// each: Tic-Tac-Toe, Nim and peg solitaire searched to the end, and Mancala
+// cut off at a horizon. Nim's piles start out of order so that table entries
+// get shared between positions whose piles are listed differently.
+var gameCases = []struct {
+	name  string
+	check func(t *testing.T, base mode, variant mode)
+}{
+	{"Tic-Tac-Toe", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, tictactoe.TicTacToe(), 3, 9, nil, base, variant)
+	}},
+	{"Nim", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, nim.NimGame(nim.NimState{1, 2, 3, 4}, 0, false), 3, 10, nil, base, variant)
+	}},
+	{"Nim, piles out of order", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, nim.NimGame(nim.NimState{3, 1, 3, 2}, 0, false), 3, 9, nil, base, variant)
+	}},
+	{"Misere Nim", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, nim.NimGame(nim.NimState{2, 3, 4}, 2, true), 3, 9, nil, base, variant)
+	}},
+	{"Peg solitaire", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, peg_solitaire.TrianglePegSolitaire(), 3, 14, nil, base, variant)
+	}},
+	{"Mancala", func(t *testing.T, base mode, variant mode) {
+		expectSameRatings(t, mancala.MancalaGame(3, 3), 6, 6, mancala.Evaluator(), base, variant)
+	}},
+}
+
+func TestAlphaBetaMatchesFullSearch(t *testing.T) {
+	for _, tc := range gameCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check(t, mode{}, mode{alphaBeta: true})
+		})
+	}
+}
+
+func TestParallelMatchesSerial(t *testing.T) {
+	for _, tc := range gameCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check(t, mode{}, mode{workers: 4})
+			tc.check(t, mode{alphaBeta: true}, mode{alphaBeta: true, workers: 4})
+		})
+	}
+}
+
+// playLine plays the moves with the given summaries from prospect, and fails
+// if any of them isn't legal.
+func playLine[State games.GameState](t *testing.T, game games.Game[State], prospect games.Prospect[State], line []string) games.Prospect[State] {
+	t.Helper()
+
+	for _, summary := range line {
+		found := false
+		for _, move := range game.Describe(prospect).Moves {
+			if move.Summary == summary {
+				prospect, found = prospect.After(move), true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("illegal principal variation: %v", line)
+		}
+	}
+	return prospect
+}
+
+// TestPrincipalVariation checks that every move's principal variation can be
+// played out to the lookahead, and that it ends in a position the evaluator
+// gives the move's score, including in games whose table entries are shared
+// between mirror images.
+func TestPrincipalVariation(t *testing.T) {
+	game := connect_four.ConnectFour()
+	evaluator := connect_four.Evaluator()
+	prospect := games.Prospect[connect_four.ConnectFourState]{State: game.InitialState(), FirstAgent: true}
+
+	for _, md := range []mode{{}, {alphaBeta: true}, {alphaBeta: true, workers: 4}} {
+		lookahead := 4
+		for _, rating := range newMinimaxer(game, lookahead, evaluator, md).RateChoices(prospect) {
+			line := rating.PrincipalVariation
+			if len(line) != lookahead+1 {
+				t.Errorf("%+v: %s has a %d-move principal variation, want %d: %v", md, rating.Move.Summary, len(line), lookahead+1, line)
+				continue
+			}
+
+			end := playLine(t, game, prospect, line)
+			if got := minimaxer.ToFirstAgent(rating.Score, prospect.FirstAgent); got != evaluator.Evaluate(end) {
+				t.Errorf("%+v: %s scores %d, but its principal variation ends in a position evaluated at %d", md, rating.Move.Summary, got, evaluator.Evaluate(end))
+			}
+		}
+	}
+}
+
+// TestPrincipalVariationToTheEnd checks that in a game searched to the end,
+// every move's principal variation finishes the game in as many moves as the
+// rating says, with its score. Nim's table entries are shared between
+// positions whose piles are listed in different orders, so the moves the line
+// takes from the table have to be mapped onto each position's own piles.
+// Alpha-beta leaves bounds in the table that can cut a line short where it
+// runs into a transposition, so only searches without it are checked.
+func TestPrincipalVariationToTheEnd(t *testing.T) {
+	game := nim.NimGame(nim.NimState{3, 1, 3, 2}, 0, false)
+
+	for _, prospect := range line(game, 3) {
+		for _, md := range []mode{{}, {workers: 4}} {
+			for _, rating := range newMinimaxer(game, 9, nil, md).RateChoices(prospect) {
+				line := rating.PrincipalVariation
+				if !rating.Decided || len(line) != rating.Distance {
+					t.Errorf("%+v: %s, %s, has a %d-move principal variation: %v", md, rating.Move.Summary, rating.ScoreString(), len(line), line)
+					continue
+				}
+
+				sd := game.Describe(playLine(t, game, prospect, line))
+				if len(sd.Moves) != 0 || sd.Score*games.EvaluationScale != minimaxer.ToFirstAgent(rating.Score, prospect.FirstAgent) {
+					t.Errorf("%+v: %s scores %s, but its principal variation ends with Score %d", md, rating.Move.Summary, rating.ScoreString(), sd.Score)
+				}
+			}
+		}
+	}
+}
+
+// TestTurnDepthMatchesSearchWithoutTable checks that counting depth in turns
+// gives the same scores whatever the table holds, by comparing against a
+// search whose table is too small to remember anything.
+func TestTurnDepthMatchesSearchWithoutTable(t *testing.T) {
+	game := mancala.MancalaGame(4, 3)
+	noTable := mode{turnDepth: 2, tableSize: 2}
+	for _, md := range []mode{{turnDepth: 2}, {turnDepth: 2, alphaBeta: true}, {turnDepth: 2, alphaBeta: true, workers: 4}} {
+		expectSameRatings(t, game, 12, 5, nil, noTable, md)
+	}
+}
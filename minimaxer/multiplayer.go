@@ -0,0 +1,223 @@
+package minimaxer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+// MultiMinimaxer searches games for any number of players. By default it uses
+// max^n, where each player is assumed to pick the move best for their own
+// score. In paranoid mode, the other players are instead assumed to be working
+// together against the player choosing a move.
+type MultiMinimaxer[State games.GameState] struct {
+	game      games.MultiGame[State]
+	lookahead int
+	paranoid  bool
+	rng       *rand.Rand
+	table     *table[[]int]
+	tableSize int
+	nodes     int
+}
+
+func NewMultiMinimaxer[State games.GameState](game games.MultiGame[State], lookahead int) *MultiMinimaxer[State] {
+	return &MultiMinimaxer[State]{
+		game:      game,
+		lookahead: lookahead,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		tableSize: defaultTableSize,
+	}
+}
+
+// WithParanoid switches to paranoid search, which reduces the game to two
+// sides and so can prune with alpha-beta.
+func (m *MultiMinimaxer[State]) WithParanoid() *MultiMinimaxer[State] {
+	m.paranoid = true
+	return m
+}
+
+// WithTableSize caps how many scored positions the search remembers, as
+// Minimaxer.WithTableSize does.
+func (m *MultiMinimaxer[State]) WithTableSize(entries int) *MultiMinimaxer[State] {
+	m.tableSize = entries
+	return m
+}
+
+// WithSeed makes the choice between equally good moves come from a source
+// seeded with seed.
+func (m *MultiMinimaxer[State]) WithSeed(seed int64) *MultiMinimaxer[State] {
+	return m.WithRandSource(rand.NewSource(seed))
+}
+
+// WithRandSource makes the choice between equally good moves come from src.
+func (m *MultiMinimaxer[State]) WithRandSource(src rand.Source) *MultiMinimaxer[State] {
+	m.rng = rand.New(src)
+	return m
+}
+
+func (m *MultiMinimaxer[State]) Name() string {
+	return fmt.Sprintf("MultiMinimaxer @%p", m)
+}
+
+func (m *MultiMinimaxer[State]) ChooseMove(prospect games.MultiProspect[State]) games.MultiMove[State] {
+	m.table = newTable[[]int](m.tableSize)
+	m.nodes = 0
+
+	player := prospect.Player
+	sd := m.game.Describe(prospect)
+
+	score := 0
+	var goodMoves []games.MultiMove[State]
+
+	for i, move := range sd.Moves {
+		child := games.MultiProspect[State]{State: move.State, Player: move.NextPlayer}
+
+		var ps int
+		if m.paranoid {
+			alpha := -infinity
+			if i > 0 {
+				alpha = score - 1
+			}
+			ps = m.paranoidSearch(child, player, m.lookahead-1, alpha, infinity)
+		} else {
+			ps = m.maxN(child, m.lookahead-1)[player]
+		}
+
+		if i == 0 || ps > score {
+			score = ps
+			goodMoves = []games.MultiMove[State]{move}
+		} else if ps == score {
+			goodMoves = append(goodMoves, move)
+		}
+	}
+
+	return goodMoves[m.rng.Intn(len(goodMoves))]
+}
+
+func (m *MultiMinimaxer[State]) Comment() string {
+	return fmt.Sprintf("I analyzed %d game states!", m.nodes)
+}
+
+// maxN scores a prospect for every player, assuming each picks the move that
+// is best for themselves. The scores are kept in the table's extra field.
+func (m *MultiMinimaxer[State]) maxN(prospect games.MultiProspect[State], searchDepth int) []int {
+	key := prospect.Hash()
+	if entry, ok := m.table.lookup(key); ok && entry.depth == searchDepth {
+		return entry.extra
+	}
+	m.nodes++
+
+	sd := m.game.Describe(prospect)
+
+	var scores []int
+	if len(sd.Outcomes) > 0 && searchDepth > 0 {
+		expected := make([]float64, len(sd.Scores))
+		for _, outcome := range sd.Outcomes {
+			child := games.MultiProspect[State]{State: outcome.Move.State, Player: outcome.Move.NextPlayer}
+			for i, score := range m.maxN(child, searchDepth-1) {
+				expected[i] += outcome.Probability * float64(score)
+			}
+		}
+		scores = make([]int, len(expected))
+		for i, e := range expected {
+			scores[i] = int(math.Round(e))
+		}
+	} else if len(sd.Moves) == 0 || searchDepth <= 0 {
+		scores = sd.Scores
+	} else {
+		for _, move := range sd.Moves {
+			child := games.MultiProspect[State]{State: move.State, Player: move.NextPlayer}
+			ps := m.maxN(child, searchDepth-1)
+			if scores == nil || ps[prospect.Player] > scores[prospect.Player] {
+				scores = ps
+			}
+		}
+	}
+
+	m.table.store(tableEntry[[]int]{key: key, hash: key, depth: searchDepth, move: -1, extra: scores})
+	return scores
+}
+
+// paranoidSearch looks a prospect up in the table before searching it, as
+// searcher.getProspectScore does.
+func (m *MultiMinimaxer[State]) paranoidSearch(prospect games.MultiProspect[State], player int, searchDepth int, alpha int, beta int) int {
+	key := prospect.Hash()
+	if entry, ok := m.table.lookup(key); ok && entry.depth == searchDepth {
+		switch {
+		case entry.bound == exactBound,
+			entry.bound == lowerBound && entry.score >= beta,
+			entry.bound == upperBound && entry.score <= alpha:
+			return entry.score
+		}
+	}
+
+	score := m.paranoidScore(prospect, player, searchDepth, alpha, beta)
+
+	entry := tableEntry[[]int]{key: key, hash: key, score: score, depth: searchDepth, bound: exactBound, move: -1}
+	if score <= alpha {
+		entry.bound = upperBound
+	} else if score >= beta {
+		entry.bound = lowerBound
+	}
+	m.table.store(entry)
+
+	return score
+}
+
+// paranoidScore scores a prospect for player alone, assuming everyone else
+// picks whatever is worst for them. Scores outside (alpha, beta) are bounds,
+// as in searcher.search.
+func (m *MultiMinimaxer[State]) paranoidScore(prospect games.MultiProspect[State], player int, searchDepth int, alpha int, beta int) int {
+	m.nodes++
+
+	sd := m.game.Describe(prospect)
+
+	if len(sd.Outcomes) > 0 && searchDepth > 0 {
+		expected := 0.0
+		for _, outcome := range sd.Outcomes {
+			child := games.MultiProspect[State]{State: outcome.Move.State, Player: outcome.Move.NextPlayer}
+			expected += outcome.Probability * float64(m.paranoidSearch(child, player, searchDepth-1, -infinity, infinity))
+		}
+		return int(math.Round(expected))
+	}
+
+	if len(sd.Moves) == 0 || searchDepth <= 0 {
+		return sd.Scores[player]
+	}
+
+	maximizing := prospect.Player == player
+	score := infinity
+	if maximizing {
+		score = -infinity
+	}
+
+	for _, move := range sd.Moves {
+		child := games.MultiProspect[State]{State: move.State, Player: move.NextPlayer}
+		ps := m.paranoidSearch(child, player, searchDepth-1, alpha, beta)
+
+		if maximizing {
+			if ps > score {
+				score = ps
+			}
+			if score > alpha {
+				alpha = score
+			}
+		} else {
+			if ps < score {
+				score = ps
+			}
+			if score < beta {
+				beta = score
+			}
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return score
+}
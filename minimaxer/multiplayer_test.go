@@ -0,0 +1,54 @@
+package minimaxer_test
+
+import (
+	"testing"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/minimaxer"
+	"github.com/cstuartroe/minimax/nim"
+)
+
+// TestMultiMinimaxerMatchesMinimaxer plays two-player Nim through
+// games.FromTwoPlayer, and checks that from each position along a line, max^n
+// and paranoid search both choose a move that Minimaxer rates as best. Neither
+// prefers faster wins the way Minimaxer does, so only the moves' values are
+// compared.
+func TestMultiMinimaxerMatchesMinimaxer(t *testing.T) {
+	for _, misere := range []bool{false, true} {
+		game := nim.NimGame(nim.NimState{1, 2, 3, 4}, 0, misere)
+		multi := games.FromTwoPlayer(game)
+
+		for ply, prospect := range line(game, 4) {
+			ratings := minimaxer.NewMinimaxer(game, 10).RateChoices(prospect)
+			if len(ratings) == 0 {
+				continue
+			}
+			values := map[string]int{}
+			best := ratings[0].Score
+			for _, rating := range ratings {
+				values[rating.Move.Summary] = rating.Score
+				if rating.Score > best {
+					best = rating.Score
+				}
+			}
+
+			player := 1
+			if prospect.FirstAgent {
+				player = 0
+			}
+			multiProspect := games.MultiProspect[nim.NimState]{State: prospect.State, Player: player}
+
+			for _, paranoid := range []bool{false, true} {
+				m := minimaxer.NewMultiMinimaxer(multi, 10).WithSeed(1)
+				if paranoid {
+					m = m.WithParanoid()
+				}
+
+				move := m.ChooseMove(multiProspect)
+				if values[move.Summary] != best {
+					t.Errorf("misere %t, ply %d, paranoid %t: chose %q, rated %s, but the best move is rated %s", misere, ply, paranoid, move.Summary, minimaxer.FormatScore(values[move.Summary]), minimaxer.FormatScore(best))
+				}
+			}
+		}
+	}
+}
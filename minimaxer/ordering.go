@@ -0,0 +1,116 @@
+package minimaxer
+
+import (
+	"sort"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+// MoveOrdering selects which heuristics the search uses to decide what order
+// to try moves in. Good ordering doesn't change scores, but lets alpha-beta
+// pruning skip more of the tree.
+type MoveOrdering uint8
+
+const (
+	// OrderTableMove tries first whichever move was best the last time the
+	// position was searched.
+	OrderTableMove MoveOrdering = 1 << iota
+	// OrderKillers tries next the moves that most recently caused a cutoff at
+	// the same ply elsewhere in the tree.
+	OrderKillers
+	// OrderHistory prefers moves that have caused cutoffs often, weighted
+	// toward cutoffs found with more search depth left.
+	OrderHistory
+	// OrderHints falls back on the game's own move priorities, if it
+	// implements games.MovePrioritizer.
+	OrderHints
+
+	DefaultOrdering = OrderTableMove | OrderKillers | OrderHistory | OrderHints
+)
+
+type historyKey struct {
+	firstAgent bool
+	summary    string
+}
+
+// orderingHeuristics holds what one searcher has learned about which moves
+// tend to cause cutoffs.
+type orderingHeuristics struct {
+	killers [][2]string
+	history map[historyKey]int
+}
+
+func (h *orderingHeuristics) killerRank(ply int, summary string) int {
+	if ply >= len(h.killers) {
+		return 0
+	} else if h.killers[ply][0] == summary {
+		return 2
+	} else if h.killers[ply][1] == summary {
+		return 1
+	}
+	return 0
+}
+
+func (h *orderingHeuristics) recordCutoff(firstAgent bool, summary string, ply int, searchDepth int) {
+	for len(h.killers) <= ply {
+		h.killers = append(h.killers, [2]string{})
+	}
+	if h.killers[ply][0] != summary {
+		h.killers[ply][1] = h.killers[ply][0]
+		h.killers[ply][0] = summary
+	}
+
+	if h.history == nil {
+		h.history = map[historyKey]int{}
+	}
+	h.history[historyKey{firstAgent, summary}] += searchDepth * searchDepth
+}
+
+// moveOrder lists the indices of a prospect's moves in the order to search
+// them. Heuristics are only consulted if h isn't nil.
+func (m *Minimaxer[State]) moveOrder(prospect games.Prospect[State], moves []games.Move[State], key uint64, hash uint64, ply int, h *orderingHeuristics) []int {
+	first := -1
+	if m.ordering&OrderTableMove != 0 {
+		if entry, ok := m.table.lookup(key); ok {
+			first = m.tableMove(entry, prospect, hash, moves)
+		}
+	}
+
+	order := make([]int, 0, len(moves))
+	for i := range moves {
+		if i != first {
+			order = append(order, i)
+		}
+	}
+
+	killers := make([]int, len(moves))
+	history := make([]int, len(moves))
+	hints := make([]int, len(moves))
+	for _, i := range order {
+		if h != nil && m.ordering&OrderKillers != 0 {
+			killers[i] = h.killerRank(ply, moves[i].Summary)
+		}
+		if h != nil && m.ordering&OrderHistory != 0 {
+			history[i] = h.history[historyKey{prospect.FirstAgent, moves[i].Summary}]
+		}
+		if m.prioritizer != nil && m.ordering&OrderHints != 0 {
+			hints[i] = m.prioritizer.MovePriority(prospect, moves[i])
+		}
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if killers[i] != killers[j] {
+			return killers[i] > killers[j]
+		}
+		if history[i] != history[j] {
+			return history[i] > history[j]
+		}
+		return hints[i] > hints[j]
+	})
+
+	if first >= 0 && first < len(moves) {
+		order = append([]int{first}, order...)
+	}
+	return order
+}
@@ -0,0 +1,97 @@
+package minimaxer
+
+import "github.com/cstuartroe/minimax/games"
+
+// pvLine is the principal variation below one ply of the line being searched:
+// the best moves the search found, followed, if it took a score from the table
+// partway down, by the best moves recorded in the table from that position.
+type pvLine[State games.GameState] struct {
+	moves     []string
+	fromTable bool
+	tail      games.Prospect[State]
+	tailDepth int
+	tailChain int
+}
+
+func (s *searcher[State]) growPV(ply int) {
+	for len(s.pv) <= ply {
+		s.pv = append(s.pv, pvLine[State]{})
+	}
+}
+
+// resetPV empties the line at ply, for a position about to be searched.
+func (s *searcher[State]) resetPV(ply int) {
+	s.growPV(ply)
+	s.pv[ply].moves = s.pv[ply].moves[:0]
+	s.pv[ply].fromTable = false
+}
+
+// tablePV makes the line at ply follow the table from prospect, reached by a
+// chain of moves by the same player, whose score searched to searchDepth was
+// taken from there.
+func (s *searcher[State]) tablePV(ply int, prospect games.Prospect[State], searchDepth int, chain int) {
+	s.resetPV(ply)
+	s.pv[ply].fromTable = true
+	s.pv[ply].tail = prospect
+	s.pv[ply].tailDepth = searchDepth
+	s.pv[ply].tailChain = chain
+}
+
+// extendPV makes the line at ply the move with the given summary, followed by
+// the line below it.
+func (s *searcher[State]) extendPV(ply int, summary string) {
+	s.growPV(ply + 1)
+	line, next := &s.pv[ply], &s.pv[ply+1]
+	line.moves = append(append(line.moves[:0], summary), next.moves...)
+	line.fromTable, line.tail, line.tailDepth, line.tailChain = next.fromTable, next.tail, next.tailDepth, next.tailChain
+}
+
+// principalVariation lists the moves of the line at ply.
+func (s *searcher[State]) principalVariation(ply int) []string {
+	s.growPV(ply)
+	line := append([]string{}, s.pv[ply].moves...)
+	if s.pv[ply].fromTable {
+		line = append(line, s.m.tableLine(s.pv[ply].tail, s.pv[ply].tailDepth, s.pv[ply].tailChain)...)
+	}
+	return line
+}
+
+// tableLine follows the best moves recorded in the table from prospect, as
+// long as each position's entry is an exact score from a search to the depth
+// the line reaches it with, so that the moves are the ones that search chose.
+// An exact win or loss close enough to be seen from either depth also counts,
+// since a search to any such depth finds the same result. The depth and chain
+// of each position follow from the ones before it as in searcher.childScore.
+func (m *Minimaxer[State]) tableLine(prospect games.Prospect[State], searchDepth int, chain int) []string {
+	line := []string{}
+
+	for {
+		key, hash := m.tableKey(prospect)
+		entry, ok := m.table.lookup(m.chainKey(key, chain))
+		if !ok || entry.bound != exactBound {
+			break
+		}
+		if distance, decided := scoreDistance(entry.score); entry.depth != searchDepth && !(decided && distance <= searchDepth) {
+			break
+		}
+
+		moves := m.game.Describe(prospect).Moves
+		i := m.tableMove(entry, prospect, hash, moves)
+		if i < 0 {
+			break
+		}
+
+		move := moves[i]
+		line = append(line, move.Summary)
+		prospect = prospect.After(move)
+		searchDepth--
+		if m.maxChain > 0 {
+			chain = nextChain(move, chain)
+			if m.freeMove(chain) {
+				searchDepth++
+			}
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,95 @@
+package minimaxer
+
+// Search scores pack two numbers into one int. The high part is the value of
+// the position in evaluation units. The low part breaks ties between decided
+// positions: it is positive for wins and negative for losses, and its size
+// shrinks with the number of plies from the root to the end of the game, so
+// that faster wins and slower losses sort ahead.
+const (
+	distanceScale = 1 << 12
+	maxDistance   = distanceScale/2 - 1
+)
+
+// FromFirstAgent converts a score from the first agent's point of view, the
+// convention of games.StateDescriptor.Score and games.Evaluator, to the point
+// of view of the player to move, which is the convention of the search and of
+// RatedMove.
+func FromFirstAgent(score int, firstAgent bool) int {
+	if firstAgent {
+		return score
+	}
+	return -score
+}
+
+// ToFirstAgent converts a score from the point of view of the player to move
+// back to the first agent's.
+func ToFirstAgent(score int, firstAgent bool) int {
+	return FromFirstAgent(score, firstAgent)
+}
+
+// decidedScore scores a finished game found ply moves below the root.
+func decidedScore(value int, ply int) int {
+	if ply >= maxDistance {
+		ply = maxDistance - 1
+	}
+
+	tiebreak := 0
+	if value > 0 {
+		tiebreak = maxDistance - ply
+	} else if value < 0 {
+		tiebreak = ply - maxDistance
+	}
+	return value*distanceScale + tiebreak
+}
+
+// undecidedScore scores a position whose value is only an estimate.
+func undecidedScore(value int) int {
+	return value * distanceScale
+}
+
+func splitScore(score int) (value int, tiebreak int) {
+	value = score / distanceScale
+	tiebreak = score - value*distanceScale
+	if tiebreak > distanceScale/2 {
+		value++
+		tiebreak -= distanceScale
+	} else if tiebreak < -distanceScale/2 {
+		value--
+		tiebreak += distanceScale
+	}
+	return value, tiebreak
+}
+
+// scoreDistance reports how many plies below the root a decided score's game
+// ends, or false if the score isn't decided.
+func scoreDistance(score int) (int, bool) {
+	_, tiebreak := splitScore(score)
+	if tiebreak > 0 {
+		return maxDistance - tiebreak, true
+	} else if tiebreak < 0 {
+		return maxDistance + tiebreak, true
+	}
+	return 0, false
+}
+
+// shiftScore moves a decided score's distance by plies. The table stores
+// distances from the scored position rather than from the root, so scores are
+// shifted by the position's ply on the way in and back on the way out.
+func shiftScore(score int, plies int) int {
+	value, tiebreak := splitScore(score)
+	if tiebreak > 0 {
+		tiebreak = clampTiebreak(tiebreak - plies)
+	} else if tiebreak < 0 {
+		tiebreak = -clampTiebreak(-tiebreak - plies)
+	}
+	return value*distanceScale + tiebreak
+}
+
+func clampTiebreak(tiebreak int) int {
+	if tiebreak < 1 {
+		return 1
+	} else if tiebreak > maxDistance {
+		return maxDistance
+	}
+	return tiebreak
+}
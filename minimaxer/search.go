@@ -0,0 +1,238 @@
+package minimaxer
+
+import (
+	"math"
+	"time"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+// searcher holds the state of one thread of a search. Searchers working on the
+// same Minimaxer share its table, deadline and context.
+type searcher[State games.GameState] struct {
+	m          *Minimaxer[State]
+	stats      Stats
+	hitHorizon bool
+	heuristics orderingHeuristics
+	// pv holds, for each ply of the line being searched, the principal
+	// variation found below it so far.
+	pv []pvLine[State]
+	// chains holds, for each ply of the line being searched, how many moves
+	// in a row the same player has made to reach it.
+	chains []int
+}
+
+// shouldStop reports whether the search has run out of time or been cancelled,
+// only checking the clock and context every so often.
+func (s *searcher[State]) shouldStop() bool {
+	s.stats.Nodes++
+	if s.stats.Nodes%1024 == 0 {
+		if (!s.m.deadline.IsZero() && time.Now().After(s.m.deadline)) || s.m.ctx.Err() != nil {
+			s.m.stopped.Store(true)
+		}
+	}
+	return s.m.stopped.Load()
+}
+
+// search scores a prospect ply moves below the root from the point of view of
+// the player to move there, along with the index of the best move found and,
+// for games.Symmetric games, the table key of the position it leads to.
+// Scores outside the (alpha, beta) window are only bounds: a result <= alpha
+// means the true score is at most that, and a result >= beta means it is at
+// least that.
+func (s *searcher[State]) search(prospect games.Prospect[State], key uint64, hash uint64, ply int, searchDepth int, alpha int, beta int) (int, int, uint64) {
+	s.resetPV(ply)
+	if s.shouldStop() {
+		return 0, -1, 0
+	}
+
+	if s.m.oracle != nil {
+		if score, ok := s.m.oracle.Lookup(prospect); ok {
+			s.stats.OracleHits++
+			return FromFirstAgent(decidedScore(score*games.EvaluationScale, ply), prospect.FirstAgent), -1, 0
+		}
+	}
+
+	sd := s.m.game.Describe(prospect)
+
+	if len(sd.Outcomes) > 0 {
+		if searchDepth <= 0 {
+			s.hitHorizon = true
+			return FromFirstAgent(undecidedScore(s.m.evaluate(prospect, sd)), prospect.FirstAgent), -1, 0
+		}
+		return s.expectation(prospect, sd.Outcomes, ply, searchDepth), -1, 0
+	}
+
+	if len(sd.Moves) == 0 {
+		return FromFirstAgent(decidedScore(sd.Score*games.EvaluationScale, ply), prospect.FirstAgent), -1, 0
+	}
+
+	score := -infinity
+	best := -1
+
+	// Past the lookahead, the evaluation stands unless a noisy move, searched
+	// while quiescence plies remain, does better.
+	if searchDepth <= 0 {
+		s.hitHorizon = true
+		score = FromFirstAgent(undecidedScore(s.m.evaluate(prospect, sd)), prospect.FirstAgent)
+		if searchDepth <= -s.m.quiescence {
+			return score, -1, 0
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if s.m.alphaBeta && alpha >= beta {
+			return score, -1, 0
+		}
+	}
+
+	s.stats.Expanded++
+	for _, i := range s.m.moveOrder(prospect, sd.Moves, key, hash, ply, &s.heuristics) {
+		if searchDepth <= 0 && !sd.Moves[i].Noisy && !sd.Moves[i].RetainControl {
+			continue
+		}
+
+		s.stats.Children++
+		childAlpha, childBeta := -infinity, infinity
+		if s.m.alphaBeta {
+			childAlpha, childBeta = alpha, beta
+		}
+
+		ps := s.childScore(prospect, sd.Moves[i], ply+1, searchDepth-1, childAlpha, childBeta)
+		if ps > score {
+			score, best = ps, i
+			s.extendPV(ply, sd.Moves[i].Summary)
+		}
+		if score > alpha {
+			alpha = score
+		}
+
+		if s.m.alphaBeta && alpha >= beta {
+			s.stats.Cutoffs++
+			s.heuristics.recordCutoff(prospect.FirstAgent, sd.Moves[i].Summary, ply, searchDepth)
+			break
+		}
+	}
+
+	var bestKey uint64
+	if best >= 0 && s.m.symmetric != nil {
+		bestKey, _ = s.m.tableKey(prospect.After(sd.Moves[best]))
+	}
+	return score, best, bestKey
+}
+
+// childScore scores the prospect a move leads to from the point of view of the
+// player who made it, searching it with the (alpha, beta) window seen from
+// that side. The score and window are negated unless the mover kept control.
+// searchDepth is the child's depth, which WithTurnDepth gives back the ply for
+// moves that keep control.
+func (s *searcher[State]) childScore(prospect games.Prospect[State], move games.Move[State], ply int, searchDepth int, alpha int, beta int) int {
+	if s.m.maxChain > 0 {
+		chain := nextChain(move, s.chain(ply-1))
+		s.setChain(ply, chain)
+		if s.m.freeMove(chain) {
+			searchDepth++
+		}
+	}
+
+	child := prospect.After(move)
+	if child.FirstAgent == prospect.FirstAgent {
+		return s.getProspectScore(child, ply, searchDepth, alpha, beta)
+	}
+	return -s.getProspectScore(child, ply, searchDepth, -beta, -alpha)
+}
+
+func (s *searcher[State]) chain(ply int) int {
+	if ply < len(s.chains) {
+		return s.chains[ply]
+	}
+	return 0
+}
+
+func (s *searcher[State]) setChain(ply int, chain int) {
+	for len(s.chains) <= ply {
+		s.chains = append(s.chains, 0)
+	}
+	s.chains[ply] = chain
+}
+
+// nextChain returns how many moves in a row the same player has made after
+// move, if they had made chain before it.
+func nextChain[State games.GameState](move games.Move[State], chain int) int {
+	if move.RetainControl {
+		return chain + 1
+	}
+	return 0
+}
+
+// freeMove reports whether WithTurnDepth lets the move that ended a chain of
+// that many moves by the same player keep the ply it was made at.
+func (m *Minimaxer[State]) freeMove(chain int) bool {
+	return chain > 0 && chain <= m.maxChain
+}
+
+// chainHash is mixed into the table keys of positions reached by a chain of
+// moves, scaled by how much of the chain WithTurnDepth counts as free.
+const chainHash uint64 = 0xbf58476d1ce4e5b9
+
+// chainKey returns the key a position reached by a chain of moves is stored
+// under. How many of the moves it leads to are free depends on how many free
+// moves came before it, which changes how deep its search goes, so positions
+// reached with different chains can't share entries.
+func (m *Minimaxer[State]) chainKey(key uint64, chain int) uint64 {
+	if chain > m.maxChain {
+		chain = m.maxChain
+	}
+	return key ^ uint64(chain)*chainHash
+}
+
+// getProspectScore looks a prospect up in the table before searching it.
+// Scores are only reused at the depth they were searched to, so a result never
+// depends on the order in which positions happened to be visited.
+func (s *searcher[State]) getProspectScore(prospect games.Prospect[State], ply int, searchDepth int, alpha int, beta int) int {
+	key, hash := s.m.tableKey(prospect)
+	key = s.m.chainKey(key, s.chain(ply))
+
+	if entry, ok := s.m.table.lookup(key); ok && entry.depth == searchDepth {
+		score := shiftScore(entry.score, ply)
+		switch {
+		case entry.bound == exactBound,
+			entry.bound == lowerBound && score >= beta,
+			entry.bound == upperBound && score <= alpha:
+			s.stats.CacheHits++
+			s.tablePV(ply, prospect, searchDepth, s.chain(ply))
+			return score
+		}
+	}
+	s.stats.CacheMisses++
+
+	score, best, bestKey := s.search(prospect, key, hash, ply, searchDepth, alpha, beta)
+	if s.m.stopped.Load() {
+		return score
+	}
+
+	entry := tableEntry[struct{}]{key: key, hash: hash, score: shiftScore(score, -ply), depth: searchDepth, bound: exactBound, move: best, moveKey: bestKey}
+	if score <= alpha {
+		entry.bound = upperBound
+	} else if score >= beta {
+		entry.bound = lowerBound
+	}
+	s.m.table.store(entry)
+
+	return score
+}
+
+// expectation scores a chance node as the average value of its outcomes,
+// weighted by their probabilities. Outcomes are searched with a full window,
+// since one outcome's score alone can't rule out the others.
+func (s *searcher[State]) expectation(prospect games.Prospect[State], outcomes []games.Outcome[State], ply int, searchDepth int) int {
+	expected := 0.0
+	s.stats.Expanded++
+	s.stats.Children += len(outcomes)
+	for _, outcome := range outcomes {
+		ps := s.childScore(prospect, outcome.Move, ply+1, searchDepth-1, -infinity, infinity)
+		value, _ := splitScore(ps)
+		expected += outcome.Probability * float64(value)
+	}
+	return undecidedScore(int(math.Round(expected)))
+}
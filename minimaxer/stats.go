@@ -0,0 +1,76 @@
+package minimaxer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stats describes the work done by a Minimaxer's most recent search.
+type Stats struct {
+	// Nodes counts every position the search visited.
+	Nodes int
+	// CacheHits counts positions whose score was taken from the table, and
+	// CacheMisses the ones that had to be searched instead.
+	CacheHits   int
+	CacheMisses int
+	// OracleHits counts positions whose score came from an oracle.
+	OracleHits int
+	// Expanded counts positions whose moves were searched, and Children the
+	// moves searched from them, so that pruned moves aren't counted.
+	Expanded int
+	Children int
+	// Cutoffs counts the times alpha-beta pruning skipped a position's
+	// remaining moves.
+	Cutoffs int
+	// Depth is the deepest search that finished, and DepthTimes how long each
+	// depth took, starting from depth 1 when deepening iteratively.
+	Depth      int
+	DepthTimes []time.Duration
+	Elapsed    time.Duration
+}
+
+// BranchingFactor is the average number of moves searched from each position
+// that wasn't a leaf.
+func (s Stats) BranchingFactor() float64 {
+	if s.Expanded == 0 {
+		return 0
+	}
+	return float64(s.Children) / float64(s.Expanded)
+}
+
+func (s Stats) NodesPerSecond() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Nodes) / s.Elapsed.Seconds()
+}
+
+func (s Stats) String() string {
+	hitRate := 0.0
+	if lookups := s.CacheHits + s.CacheMisses; lookups > 0 {
+		hitRate = 100 * float64(s.CacheHits) / float64(lookups)
+	}
+
+	times := make([]string, len(s.DepthTimes))
+	for i, t := range s.DepthTimes {
+		times[i] = t.Round(time.Microsecond).String()
+	}
+
+	return fmt.Sprintf(
+		"depth %d in %s (%s), %d nodes at %.0f/s, cache %d hits / %d misses (%.1f%%), %d oracle hits, branching factor %.2f, %d cutoffs",
+		s.Depth, s.Elapsed.Round(time.Microsecond), strings.Join(times, ", "), s.Nodes, s.NodesPerSecond(),
+		s.CacheHits, s.CacheMisses, hitRate, s.OracleHits, s.BranchingFactor(), s.Cutoffs,
+	)
+}
+
+// add adds the counts from other, leaving the depth and timings alone.
+func (s *Stats) add(other Stats) {
+	s.Nodes += other.Nodes
+	s.CacheHits += other.CacheHits
+	s.CacheMisses += other.CacheMisses
+	s.OracleHits += other.OracleHits
+	s.Expanded += other.Expanded
+	s.Children += other.Children
+	s.Cutoffs += other.Cutoffs
+}
@@ -0,0 +1,109 @@
+package minimaxer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type bound uint8
+
+const (
+	exactBound bound = iota
+	lowerBound
+	upperBound
+)
+
+// tableEntry records a search of a position. The key is shared by symmetric
+// variants of the position, but move indexes the moves of the variant whose
+// hash is recorded, so it only applies to that one; moveKey, the key of the
+// position the move leads to, identifies it in every variant. Searches that
+// need to remember more than one score, like max^n, keep the rest in extra.
+type tableEntry[Extra any] struct {
+	key        uint64
+	hash       uint64
+	used       bool
+	score      int
+	depth      int
+	bound      bound
+	move       int
+	moveKey    uint64
+	extra      Extra
+	generation uint32
+}
+
+const defaultTableSize = 1 << 18
+
+const tableLocks = 64
+
+// table is a fixed-size transposition table. Each key hashes to a bucket of
+// two slots: the first keeps the deepest search seen there, the second always
+// takes whatever the first turned away, so the table never grows past its size.
+// Entries left over from earlier searches count as stale, and give up the first
+// slot to anything from the current search regardless of depth.
+// Buckets are guarded by a set of striped locks so searchers can share a table.
+type table[Extra any] struct {
+	entries    []tableEntry[Extra]
+	locks      [tableLocks]sync.Mutex
+	size       atomic.Int64
+	generation uint32
+}
+
+func newTable[Extra any](size int) *table[Extra] {
+	if size < 2 {
+		size = 2
+	}
+	return &table[Extra]{entries: make([]tableEntry[Extra], size-size%2)}
+}
+
+// newSearch marks every entry currently in the table as stale.
+func (t *table[Extra]) newSearch() {
+	t.generation++
+}
+
+func (t *table[Extra]) bucket(key uint64) ([]tableEntry[Extra], *sync.Mutex) {
+	i := key % uint64(len(t.entries)/2)
+	return t.entries[2*i : 2*i+2], &t.locks[i%tableLocks]
+}
+
+func (t *table[Extra]) lookup(key uint64) (tableEntry[Extra], bool) {
+	b, lock := t.bucket(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, entry := range b {
+		if entry.used && entry.key == key {
+			return entry, true
+		}
+	}
+	return tableEntry[Extra]{}, false
+}
+
+func (t *table[Extra]) put(slot *tableEntry[Extra], entry tableEntry[Extra]) {
+	if !slot.used && entry.used {
+		t.size.Add(1)
+	} else if slot.used && !entry.used {
+		t.size.Add(-1)
+	}
+	*slot = entry
+}
+
+func (t *table[Extra]) store(entry tableEntry[Extra]) {
+	entry.used = true
+	entry.generation = t.generation
+	b, lock := t.bucket(entry.key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if b[1].used && b[1].key == entry.key {
+		t.put(&b[1], tableEntry[Extra]{})
+	}
+
+	if !b[0].used || b[0].key == entry.key || b[0].generation != t.generation || entry.depth >= b[0].depth {
+		if b[0].used && b[0].key != entry.key {
+			t.put(&b[1], b[0])
+		}
+		t.put(&b[0], entry)
+	} else {
+		t.put(&b[1], entry)
+	}
+}
@@ -2,6 +2,7 @@ package nim
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cstuartroe/minimax/games"
 )
@@ -16,6 +17,11 @@ func (s NimState) String() string {
 	return out
 }
 
+// Hash is an FNV-1a hash of the pile sizes.
+func (s NimState) Hash() uint64 {
+	return games.HashInts(s...)
+}
+
 type nimGame struct {
 	initialState NimState
 	maxTake      int
@@ -30,18 +36,26 @@ func (g nimGame) InitialState() NimState {
 	return g.initialState
 }
 
-func (g nimGame) Describe(prospect games.Prospect[NimState]) games.StateDescriptor[NimState] {
+// Canonical sorts the piles, since a position plays the same whatever order
+// its piles are in.
+func (g nimGame) Canonical(state NimState) NimState {
+	out := append(NimState{}, state...)
+	sort.Ints(out)
+	return out
+}
+
+func takes(state NimState, maxTake int) []games.Move[NimState] {
 	moves := []games.Move[NimState]{}
 
-	for i, pile := range prospect.State {
-		turnMaxTake := g.maxTake
-		if g.maxTake == 0 || g.maxTake > pile {
+	for i, pile := range state {
+		turnMaxTake := maxTake
+		if maxTake == 0 || maxTake > pile {
 			turnMaxTake = pile
 		}
 
 		for take := 1; take <= turnMaxTake; take++ {
 			newState := NimState{}
-			for _, n := range prospect.State {
+			for _, n := range state {
 				newState = append(newState, n)
 			}
 			newState[i] = pile - take
@@ -55,6 +69,12 @@ func (g nimGame) Describe(prospect games.Prospect[NimState]) games.StateDescript
 		}
 	}
 
+	return moves
+}
+
+func (g nimGame) Describe(prospect games.Prospect[NimState]) games.StateDescriptor[NimState] {
+	moves := takes(prospect.State, g.maxTake)
+
 	score := 0
 	if len(moves) == 0 {
 		if prospect.FirstAgent {
@@ -74,6 +94,48 @@ func (g nimGame) Describe(prospect games.Prospect[NimState]) games.StateDescript
 	}
 }
 
+type multiNimGame struct {
+	nimGame
+	players int
+}
+
+// MultiNimGame is Nim for any number of players, who take turns in order.
+// Whoever takes the last object scores 1 and everyone else scores 0, or in
+// misere play, whoever takes the last object scores -1.
+func MultiNimGame(initialState NimState, maxTake int, misere bool, players int) games.MultiGame[NimState] {
+	return multiNimGame{nimGame{initialState, maxTake, misere}, players}
+}
+
+func (g multiNimGame) Players() int {
+	return g.players
+}
+
+func (g multiNimGame) Describe(prospect games.MultiProspect[NimState]) games.MultiStateDescriptor[NimState] {
+	next := (prospect.Player + 1) % g.players
+	moves := []games.MultiMove[NimState]{}
+	for _, move := range takes(prospect.State, g.maxTake) {
+		moves = append(moves, games.MultiMove[NimState]{
+			Summary:    move.Summary,
+			State:      move.State,
+			NextPlayer: next,
+		})
+	}
+
+	scores := make([]int, g.players)
+	if len(moves) == 0 {
+		last := (prospect.Player + g.players - 1) % g.players
+		scores[last] = 1
+		if g.misere {
+			scores[last] = -1
+		}
+	}
+
+	return games.MultiStateDescriptor[NimState]{
+		Scores: scores,
+		Moves:  moves,
+	}
+}
+
 func NimStates(total int, maxPile int) [][]int {
 	if total == 0 {
 		return [][]int{{}}
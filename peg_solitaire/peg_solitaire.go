@@ -24,6 +24,17 @@ func (s TrianglePegSolitaireState) copy() [15]bool {
 	return out
 }
 
+// Hash packs the pegs into a bitmask, which is already unique per state.
+func (s TrianglePegSolitaireState) Hash() uint64 {
+	var h uint64
+	for i, peg := range s.pegs {
+		if peg {
+			h |= 1 << i
+		}
+	}
+	return h
+}
+
 func (s TrianglePegSolitaireState) String() string {
 	out := ""
 
@@ -64,6 +75,35 @@ var peg_dimensions [][][]int = [][][]int{
 	rotate(rotate(horizontal_peg_dimension)),
 }
 
+// peg_symmetries lists the board's three rotations and three reflections, each
+// as the hole that every hole moves to. Each set of rows in peg_dimensions runs
+// parallel to one side, and a symmetry lays the horizontal rows onto the rows
+// of the same length in any of the three, in either direction.
+var peg_symmetries [][15]int = func() [][15]int {
+	out := [][15]int{}
+	for _, dim := range peg_dimensions {
+		rows := map[int][]int{}
+		for _, row := range dim {
+			rows[len(row)] = row
+		}
+
+		for _, reflect := range []bool{false, true} {
+			symmetry := [15]int{}
+			for _, row := range horizontal_peg_dimension {
+				for i, hole := range row {
+					j := i
+					if reflect {
+						j = len(row) - 1 - i
+					}
+					symmetry[hole] = rows[len(row)][j]
+				}
+			}
+			out = append(out, symmetry)
+		}
+	}
+	return out
+}()
+
 func (s TrianglePegSolitaireState) jump(from int, over int, to int) games.Move[TrianglePegSolitaireState] {
 	pegs := s.copy()
 
@@ -148,6 +188,22 @@ func (s _TrianglePegSolitaire) Describe(prospect games.Prospect[TrianglePegSolit
 	}
 }
 
+// Canonical picks, out of the board's six rotations and reflections, the one
+// with the lowest hash.
+func (s _TrianglePegSolitaire) Canonical(state TrianglePegSolitaireState) TrianglePegSolitaireState {
+	best := state
+	for _, symmetry := range peg_symmetries {
+		variant := TrianglePegSolitaireState{}
+		for hole, peg := range state.pegs {
+			variant.pegs[symmetry[hole]] = peg
+		}
+		if variant.Hash() < best.Hash() {
+			best = variant
+		}
+	}
+	return best
+}
+
 func TrianglePegSolitaire() games.Game[TrianglePegSolitaireState] {
 	return _TrianglePegSolitaire{}
 }
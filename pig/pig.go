@@ -0,0 +1,120 @@
+package pig
+
+import (
+	"fmt"
+
+	"github.com/cstuartroe/minimax/games"
+)
+
+// PigState is a game of Pig. Banked holds each player's banked points, first
+// agent first. TurnTotal is what the player to move has rolled so far this
+// turn, and Rolling is set while their die is in the air.
+type PigState struct {
+	Banked    [2]int
+	TurnTotal int
+	Rolling   bool
+}
+
+func (s PigState) String() string {
+	out := fmt.Sprintf("Banked: %d to %d, turn total: %d", s.Banked[0], s.Banked[1], s.TurnTotal)
+	if s.Rolling {
+		out += " (rolling)"
+	}
+	return out
+}
+
+func (s PigState) Hash() uint64 {
+	h := uint64(s.Banked[0])<<40 | uint64(s.Banked[1])<<16 | uint64(s.TurnTotal)<<1
+	if s.Rolling {
+		h |= 1
+	}
+	return h
+}
+
+type pigGame struct {
+	goal int
+}
+
+// PigGame is the dice game Pig. On their turn, a player rolls a die as many
+// times as they like, adding up what they roll, until they hold and bank the
+// total, or roll a 1 and lose it. The first player to bank goal points wins,
+// for a Score of 1 or -1; every other position scores 0.
+func PigGame(goal int) games.Game[PigState] {
+	return pigGame{goal}
+}
+
+func (g pigGame) InitialState() PigState {
+	return PigState{}
+}
+
+func (g pigGame) Describe(prospect games.Prospect[PigState]) games.StateDescriptor[PigState] {
+	s := prospect.State
+
+	if s.Banked[0] >= g.goal {
+		return games.StateDescriptor[PigState]{Score: 1}
+	} else if s.Banked[1] >= g.goal {
+		return games.StateDescriptor[PigState]{Score: -1}
+	}
+
+	if s.Rolling {
+		outcomes := []games.Outcome[PigState]{{
+			Move: games.Move[PigState]{
+				Summary: "Roll a 1",
+				State:   PigState{Banked: s.Banked},
+			},
+			Probability: 1.0 / 6,
+		}}
+
+		for roll := 2; roll <= 6; roll++ {
+			outcomes = append(outcomes, games.Outcome[PigState]{
+				Move: games.Move[PigState]{
+					Summary:       fmt.Sprintf("Roll a %d", roll),
+					State:         PigState{Banked: s.Banked, TurnTotal: s.TurnTotal + roll},
+					RetainControl: true,
+				},
+				Probability: 1.0 / 6,
+			})
+		}
+
+		return games.StateDescriptor[PigState]{Outcomes: outcomes}
+	}
+
+	player := 1
+	if prospect.FirstAgent {
+		player = 0
+	}
+	banked := s.Banked
+	banked[player] += s.TurnTotal
+
+	moves := []games.Move[PigState]{
+		{
+			Summary:       "Roll",
+			State:         PigState{Banked: s.Banked, TurnTotal: s.TurnTotal, Rolling: true},
+			RetainControl: true,
+		},
+	}
+	if s.TurnTotal > 0 {
+		moves = append(moves, games.Move[PigState]{
+			Summary: fmt.Sprintf("Hold and bank %d", s.TurnTotal),
+			State:   PigState{Banked: banked},
+		})
+	}
+
+	return games.StateDescriptor[PigState]{Moves: moves}
+}
+
+type evaluator struct {
+	goal int
+}
+
+// Evaluator scores Pig positions with the given goal by the difference between
+// the players' banked points, as a fraction of the goal, so that no estimate
+// reaches the Score of a win.
+func Evaluator(goal int) games.Evaluator[PigState] {
+	return evaluator{goal}
+}
+
+func (e evaluator) Evaluate(prospect games.Prospect[PigState]) int {
+	s := prospect.State
+	return (s.Banked[0] - s.Banked[1]) * (games.EvaluationScale - 1) / e.goal
+}
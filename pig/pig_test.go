@@ -0,0 +1,29 @@
+package pig_test
+
+import (
+	"testing"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/minimaxer"
+	"github.com/cstuartroe/minimax/pig"
+)
+
+// TestHoldsWinningTotal checks that a player who can bank enough to win does,
+// rather than rolling on for a bigger margin.
+func TestHoldsWinningTotal(t *testing.T) {
+	const goal = 12
+	game := pig.PigGame(goal)
+	prospect := games.Prospect[pig.PigState]{State: pig.PigState{Banked: [2]int{10, 0}, TurnTotal: 2}, FirstAgent: true}
+
+	m := minimaxer.NewMinimaxer(game, 6).WithEvaluator(pig.Evaluator(goal))
+	var best minimaxer.RatedMove[pig.PigState]
+	for i, rating := range m.RateChoices(prospect) {
+		if i == 0 || rating.Score > best.Score {
+			best = rating
+		}
+	}
+
+	if best.Move.Summary != "Hold and bank 2" {
+		t.Errorf("best move is %q at %s", best.Move.Summary, minimaxer.FormatScore(best.Score))
+	}
+}
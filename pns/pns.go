@@ -0,0 +1,244 @@
+package pns
+
+import "github.com/cstuartroe/minimax/games"
+
+// Status is what a proof-number search found out about a position.
+type Status uint8
+
+const (
+	// Unknown means the search ran out of nodes before settling the position.
+	Unknown Status = iota
+	// Proven means the attacker can force a win.
+	Proven
+	// Disproven means the defender can stop the attacker from winning.
+	Disproven
+)
+
+func (s Status) String() string {
+	switch s {
+	case Proven:
+		return "proven"
+	case Disproven:
+		return "disproven"
+	}
+	return "unknown"
+}
+
+// infinity stands in for the proof or disproof number of a position that
+// can't be proven or disproven. Sums are capped at it.
+const infinity = 1 << 30
+
+// Prover runs proof-number search, which settles whether the player to move,
+// the attacker, can force a win. Rather than searching to a fixed depth, it
+// keeps expanding whichever position looks cheapest to settle the question,
+// judged by how many unexpanded positions would still need to be won (the
+// proof number) or lost (the disproof number).
+type Prover[State games.GameState] struct {
+	game   games.Game[State]
+	budget int
+	target int
+}
+
+// NewProver returns a Prover that creates at most budget nodes per search.
+func NewProver[State games.GameState](game games.Game[State], budget int) *Prover[State] {
+	return &Prover[State]{
+		game:   game,
+		budget: budget,
+		target: 1,
+	}
+}
+
+// WithTarget counts any final score of at least target, from the attacker's
+// point of view, as a win. The default, 1, means coming out ahead at all; for
+// a one-player game like peg solitaire, it can be the score worth proving
+// reachable.
+func (p *Prover[State]) WithTarget(target int) *Prover[State] {
+	p.target = target
+	return p
+}
+
+// Strategy is a tree of play that wins for the attacker. Where the attacker is
+// to move, Next holds the one move to play. Where the defender is to move, or
+// chance decides, Next holds a way to go on winning after each possibility.
+type Strategy[State games.GameState] struct {
+	Prospect games.Prospect[State]
+	// Move is the summary of the move or outcome that led here, or empty at
+	// the root.
+	Move string
+	Next []*Strategy[State]
+}
+
+// Line lists the moves of one path through the strategy, always following
+// the first possibility, down to the end of the game.
+func (s *Strategy[State]) Line() []string {
+	line := []string{}
+	for len(s.Next) > 0 {
+		s = s.Next[0]
+		line = append(line, s.Move)
+	}
+	return line
+}
+
+// Result is the outcome of a search. Strategy is only set if Status is Proven.
+type Result[State games.GameState] struct {
+	Status   Status
+	Nodes    int
+	Strategy *Strategy[State]
+}
+
+type node[State games.GameState] struct {
+	prospect games.Prospect[State]
+	move     string
+	parent   *node[State]
+	children []*node[State]
+
+	// or nodes are where the attacker picks a move, so proving any child
+	// proves the node. Everywhere else, every child has to be proven.
+	or bool
+	pn int
+	dn int
+
+	sd games.StateDescriptor[State]
+	// settled is another node for the same position that was already proven
+	// or disproven, whose children stand in for this node's.
+	settled *node[State]
+}
+
+// Prove searches prospect, with its player to move as the attacker, until the
+// result is settled or the node budget runs out.
+func (p *Prover[State]) Prove(prospect games.Prospect[State]) Result[State] {
+	attacker := prospect.FirstAgent
+	nodes := 0
+	// Positions can be reached by more than one line of play, so once one is
+	// settled, the result is shared with the other nodes for it.
+	settled := map[uint64]*node[State]{}
+
+	newNode := func(prospect games.Prospect[State], move string, parent *node[State]) *node[State] {
+		nodes++
+		n := &node[State]{
+			prospect: prospect,
+			move:     move,
+			parent:   parent,
+			sd:       p.game.Describe(prospect),
+			pn:       1,
+			dn:       1,
+		}
+		n.or = len(n.sd.Outcomes) == 0 && prospect.FirstAgent == attacker
+
+		if other, ok := settled[prospect.Hash()]; ok {
+			n.pn, n.dn = other.pn, other.dn
+			n.settled = other
+		} else if len(n.sd.Moves) == 0 && len(n.sd.Outcomes) == 0 {
+			score := n.sd.Score
+			if !attacker {
+				score = -score
+			}
+			if score >= p.target {
+				n.pn, n.dn = 0, infinity
+			} else {
+				n.pn, n.dn = infinity, 0
+			}
+		}
+		return n
+	}
+
+	root := newNode(prospect, "", nil)
+	for root.pn != 0 && root.dn != 0 && nodes < p.budget {
+		n := mostProving(root)
+
+		moves := n.sd.Moves
+		if len(n.sd.Outcomes) > 0 {
+			moves = make([]games.Move[State], len(n.sd.Outcomes))
+			for i, outcome := range n.sd.Outcomes {
+				moves[i] = outcome.Move
+			}
+		}
+		for _, move := range moves {
+			child := newNode(n.prospect.After(move), move.Summary, n)
+			n.children = append(n.children, child)
+		}
+		n.sd = games.StateDescriptor[State]{}
+
+		for ; n != nil; n = n.parent {
+			n.update()
+			if n.pn == 0 || n.dn == 0 {
+				settled[n.prospect.Hash()] = n
+			}
+		}
+	}
+
+	result := Result[State]{Nodes: nodes}
+	switch {
+	case root.pn == 0:
+		result.Status = Proven
+		result.Strategy = root.strategy()
+	case root.dn == 0:
+		result.Status = Disproven
+	}
+	return result
+}
+
+// mostProving follows the children that are cheapest to settle down from n,
+// the cheapest to prove where the attacker moves and the cheapest to disprove
+// elsewhere, to an unexpanded node.
+func mostProving[State games.GameState](n *node[State]) *node[State] {
+	for len(n.children) > 0 {
+		best := n.children[0]
+		for _, child := range n.children[1:] {
+			if (n.or && child.pn < best.pn) || (!n.or && child.dn < best.dn) {
+				best = child
+			}
+		}
+		n = best
+	}
+	return n
+}
+
+func (n *node[State]) update() {
+	if len(n.children) == 0 {
+		return
+	}
+
+	if n.or {
+		n.pn, n.dn = infinity, 0
+		for _, child := range n.children {
+			if child.pn < n.pn {
+				n.pn = child.pn
+			}
+			n.dn = capped(n.dn + child.dn)
+		}
+	} else {
+		n.pn, n.dn = 0, infinity
+		for _, child := range n.children {
+			n.pn = capped(n.pn + child.pn)
+			if child.dn < n.dn {
+				n.dn = child.dn
+			}
+		}
+	}
+}
+
+func capped(n int) int {
+	if n > infinity {
+		return infinity
+	}
+	return n
+}
+
+// strategy builds the winning strategy below a proven node.
+func (n *node[State]) strategy() *Strategy[State] {
+	s := &Strategy[State]{Prospect: n.prospect, Move: n.move}
+	children := n.children
+	if n.settled != nil {
+		children = n.settled.children
+	}
+	for _, child := range children {
+		if child.pn == 0 {
+			s.Next = append(s.Next, child.strategy())
+			if n.or {
+				break
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,264 @@
+package solver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/internal/countio"
+)
+
+var ErrChanceNode = errors.New("solver: games with chance nodes can't be solved")
+
+// Result is the value of a position under perfect play: the final Score, and
+// how many moves away the end of the game is. The player who comes out ahead
+// ends the game as soon as they can, and the other holds out as long as they
+// can.
+type Result struct {
+	Score    int
+	Distance int
+}
+
+// betterFor reports whether r is a better result than other for the first
+// agent, if firstAgent is set, or else for the second agent.
+func (r Result) betterFor(firstAgent bool, other Result) bool {
+	score, otherScore := r.Score, other.Score
+	if !firstAgent {
+		score, otherScore = -score, -otherScore
+	}
+
+	if score != otherScore {
+		return score > otherScore
+	} else if score < 0 {
+		return r.Distance > other.Distance
+	}
+	return r.Distance < other.Distance
+}
+
+// Table holds the Result of every position reachable from a game's initial
+// state, keyed by games.Prospect.Hash.
+type Table[State games.GameState] struct {
+	keys    []uint64
+	results []Result
+}
+
+// Solve searches the whole game tree from game's initial state, with the
+// first agent to move, and records the result of every position in it.
+func Solve[State games.GameState](game games.Game[State]) (*Table[State], error) {
+	results := map[uint64]Result{}
+
+	var solve func(prospect games.Prospect[State]) (Result, error)
+	solve = func(prospect games.Prospect[State]) (Result, error) {
+		key := prospect.Hash()
+		if result, ok := results[key]; ok {
+			return result, nil
+		}
+
+		sd := game.Describe(prospect)
+		if len(sd.Outcomes) > 0 {
+			return Result{}, ErrChanceNode
+		}
+
+		result := Result{Score: sd.Score}
+		for i, move := range sd.Moves {
+			child, err := solve(prospect.After(move))
+			if err != nil {
+				return Result{}, err
+			}
+
+			child.Distance++
+			if i == 0 || child.betterFor(prospect.FirstAgent, result) {
+				result = child
+			}
+		}
+
+		results[key] = result
+		return result, nil
+	}
+
+	if _, err := solve(games.Prospect[State]{State: game.InitialState(), FirstAgent: true}); err != nil {
+		return nil, err
+	}
+
+	t := &Table[State]{}
+	for key := range results {
+		t.keys = append(t.keys, key)
+	}
+	sort.Slice(t.keys, func(i, j int) bool { return t.keys[i] < t.keys[j] })
+	for _, key := range t.keys {
+		t.results = append(t.results, results[key])
+	}
+
+	return t, nil
+}
+
+func (t *Table[State]) Len() int {
+	return len(t.keys)
+}
+
+func (t *Table[State]) Lookup(prospect games.Prospect[State]) (Result, bool) {
+	key := prospect.Hash()
+	i := sort.Search(len(t.keys), func(i int) bool { return t.keys[i] >= key })
+	if i == len(t.keys) || t.keys[i] != key {
+		return Result{}, false
+	}
+	return t.results[i], true
+}
+
+var tableMagic = [4]byte{'M', 'M', 'X', 'S'}
+
+type tableRecord struct {
+	Key      uint64
+	Score    int32
+	Distance uint16
+}
+
+// WriteTo writes the table as a short header followed by one 14-byte record
+// per position, sorted by key. Records are buffered, so w sees a few large
+// writes rather than one per position.
+func (t *Table[State]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countio.Writer{W: w}
+	bw := bufio.NewWriter(cw)
+
+	if err := binary.Write(bw, binary.LittleEndian, tableMagic); err != nil {
+		return cw.N, err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, uint64(len(t.keys))); err != nil {
+		return cw.N, err
+	}
+
+	for i, key := range t.keys {
+		record := tableRecord{
+			Key:      key,
+			Score:    int32(t.results[i].Score),
+			Distance: uint16(t.results[i].Distance),
+		}
+		if err := binary.Write(bw, binary.LittleEndian, record); err != nil {
+			return cw.N, err
+		}
+	}
+
+	err := bw.Flush()
+	return cw.N, err
+}
+
+// readChunk is how many records ReadTable reads at a time, so that it only
+// makes room for as many as the file turns out to hold, whatever its header
+// claims.
+const readChunk = 1 << 16
+
+// ReadTable reads a table written by Table.WriteTo.
+func ReadTable[State games.GameState](r io.Reader) (*Table[State], error) {
+	var magic [4]byte
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != tableMagic {
+		return nil, fmt.Errorf("solver: not a solved game table")
+	}
+
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+
+	t := &Table[State]{}
+	for left := count; left > 0; {
+		n := left
+		if n > readChunk {
+			n = readChunk
+		}
+		left -= n
+
+		records := make([]tableRecord, n)
+		if err := binary.Read(r, binary.LittleEndian, records); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		for _, record := range records {
+			if len(t.keys) > 0 && record.Key <= t.keys[len(t.keys)-1] {
+				return nil, fmt.Errorf("solver: table keys out of order")
+			}
+			t.keys = append(t.keys, record.Key)
+			t.results = append(t.results, Result{Score: int(record.Score), Distance: int(record.Distance)})
+		}
+	}
+
+	return t, nil
+}
+
+// PerfectPlayer plays by looking up every move's result in a solved table.
+type PerfectPlayer[State games.GameState] struct {
+	game   games.Game[State]
+	table  *Table[State]
+	rng    *rand.Rand
+	result Result
+	known  bool
+}
+
+func NewPerfectPlayer[State games.GameState](game games.Game[State], table *Table[State]) *PerfectPlayer[State] {
+	return &PerfectPlayer[State]{
+		game:  game,
+		table: table,
+		rng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// WithSeed makes the choice between equally good moves come from a source
+// seeded with seed.
+func (p *PerfectPlayer[State]) WithSeed(seed int64) *PerfectPlayer[State] {
+	return p.WithRandSource(rand.NewSource(seed))
+}
+
+// WithRandSource makes the choice between equally good moves come from src.
+func (p *PerfectPlayer[State]) WithRandSource(src rand.Source) *PerfectPlayer[State] {
+	p.rng = rand.New(src)
+	return p
+}
+
+func (p *PerfectPlayer[State]) Name() string {
+	return fmt.Sprintf("PerfectPlayer @%p", p)
+}
+
+func (p *PerfectPlayer[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
+	moves := p.game.Describe(prospect).Moves
+
+	p.known = false
+	var goodMoves []games.Move[State]
+	for _, move := range moves {
+		result, ok := p.table.Lookup(prospect.After(move))
+		if !ok {
+			continue
+		}
+
+		result.Distance++
+		if !p.known || result.betterFor(prospect.FirstAgent, p.result) {
+			p.result = result
+			p.known = true
+			goodMoves = []games.Move[State]{move}
+		} else if result == p.result {
+			goodMoves = append(goodMoves, move)
+		}
+	}
+
+	if len(goodMoves) == 0 {
+		return moves[0]
+	}
+	return goodMoves[p.rng.Intn(len(goodMoves))]
+}
+
+func (p *PerfectPlayer[State]) Comment() string {
+	if !p.known {
+		return "I've never seen this position before!"
+	}
+	return fmt.Sprintf("With perfect play, the game ends in %d with a score of %d.", p.result.Distance, p.result.Score)
+}
@@ -0,0 +1,85 @@
+package solver_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/solver"
+	"github.com/cstuartroe/minimax/tictactoe"
+)
+
+func TestReadTable(t *testing.T) {
+	game := tictactoe.TicTacToe()
+	table, err := solver.Solve(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written := buf.Bytes()
+
+	read, err := solver.ReadTable[tictactoe.TicTacToeBoard](bytes.NewReader(written))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Len() != table.Len() {
+		t.Errorf("read %d positions, wrote %d", read.Len(), table.Len())
+	}
+	start := games.Prospect[tictactoe.TicTacToeBoard]{State: game.InitialState(), FirstAgent: true}
+	want, _ := table.Lookup(start)
+	if got, ok := read.Lookup(start); !ok || got != want {
+		t.Errorf("initial position read as %v, %t, want %v", got, ok, want)
+	}
+
+	// A count far past the end of the file has to fail rather than try to
+	// make room for every record it claims.
+	huge := append([]byte{}, written...)
+	binary.LittleEndian.PutUint64(huge[4:12], 1<<62)
+	if _, err := solver.ReadTable[tictactoe.TicTacToeBoard](bytes.NewReader(huge)); err == nil {
+		t.Error("read a table whose count runs past the end of the file")
+	}
+
+	// Lookup needs the keys in order.
+	swapped := append([]byte{}, written...)
+	first, second := swapped[12:26], append([]byte{}, swapped[26:40]...)
+	copy(swapped[26:40], first)
+	copy(swapped[12:26], second)
+	if _, err := solver.ReadTable[tictactoe.TicTacToeBoard](bytes.NewReader(swapped)); err == nil {
+		t.Error("read a table with keys out of order")
+	}
+}
+
+// TestSolveTicTacToe checks the solved value of the opening, and that a
+// perfect player keeps to it: every move it picks leads to a position with
+// the same Score, one move closer to the end.
+func TestSolveTicTacToe(t *testing.T) {
+	game := tictactoe.TicTacToe()
+	table, err := solver.Solve(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prospect := games.Prospect[tictactoe.TicTacToeBoard]{State: game.InitialState(), FirstAgent: true}
+	want := solver.Result{Score: 0, Distance: 9}
+	if got, ok := table.Lookup(prospect); !ok || got != want {
+		t.Fatalf("opening solved as %v, %t, want %v", got, ok, want)
+	}
+
+	player := solver.NewPerfectPlayer(game, table).WithSeed(1)
+	for len(game.Describe(prospect).Moves) > 0 {
+		before, _ := table.Lookup(prospect)
+		prospect = prospect.After(player.ChooseMove(prospect))
+		after, ok := table.Lookup(prospect)
+		if !ok || after.Score != before.Score || after.Distance != before.Distance-1 {
+			t.Fatalf("perfect player went from %v to %v, %t", before, after, ok)
+		}
+	}
+	if score := game.Describe(prospect).Score; score != 0 {
+		t.Errorf("perfect play ended with Score %d, want a draw", score)
+	}
+}
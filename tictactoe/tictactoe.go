@@ -2,6 +2,7 @@ package tictactoe
 
 import (
 	"fmt"
+	"math/rand"
 
 	"github.com/cstuartroe/minimax/games"
 )
@@ -27,6 +28,34 @@ func (board TicTacToeBoard) String() string {
 	return string(out)
 }
 
+var zobristKeys map[TicTacToeSquare][3][3]uint64 = func() map[TicTacToeSquare][3][3]uint64 {
+	r := rand.New(rand.NewSource(3))
+	keys := map[TicTacToeSquare][3][3]uint64{}
+	for _, square := range []TicTacToeSquare{X, O} {
+		squareKeys := [3][3]uint64{}
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				squareKeys[y][x] = r.Uint64()
+			}
+		}
+		keys[square] = squareKeys
+	}
+	return keys
+}()
+
+// Hash is a Zobrist hash of the board.
+func (board TicTacToeBoard) Hash() uint64 {
+	var h uint64
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			if board[y][x] != Space {
+				h ^= zobristKeys[board[y][x]][y][x]
+			}
+		}
+	}
+	return h
+}
+
 type TicTacToeIndex struct {
 	x int
 	y int
@@ -123,6 +152,44 @@ func (t _TicTacToe) Describe(prospect games.Prospect[TicTacToeBoard]) games.Stat
 	return out
 }
 
+// Canonical picks, out of the board's eight rotations and reflections, the one
+// with the lowest hash.
+func (t _TicTacToe) Canonical(board TicTacToeBoard) TicTacToeBoard {
+	best := board
+	variant := board
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			variant = transpose(variant)
+		} else if i > 0 {
+			variant = rotate(variant)
+		}
+		if variant.Hash() < best.Hash() {
+			best = variant
+		}
+	}
+	return best
+}
+
+func rotate(board TicTacToeBoard) TicTacToeBoard {
+	out := TicTacToeBoard{}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			out[x][2-y] = board[y][x]
+		}
+	}
+	return out
+}
+
+func transpose(board TicTacToeBoard) TicTacToeBoard {
+	out := TicTacToeBoard{}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			out[x][y] = board[y][x]
+		}
+	}
+	return out
+}
+
 func TicTacToe() games.Game[TicTacToeBoard] {
 	return _TicTacToe{}
 }
@@ -0,0 +1,313 @@
+package training
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/cstuartroe/minimax/gameplay"
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/minimaxer"
+)
+
+// Featurizer describes positions of a game as a fixed-length list of numbers,
+// from the first agent's point of view, for a learned evaluator to weigh.
+// FeatureCount is the length of every list Features returns.
+type Featurizer[State games.GameState] interface {
+	Features(games.Prospect[State]) []float64
+	FeatureCount() int
+}
+
+// LinearEvaluator predicts a position's final Score as a weighted sum of its
+// features. It implements games.Evaluator, so it can be handed to
+// Minimaxer.WithEvaluator.
+type LinearEvaluator[State games.GameState] struct {
+	featurizer Featurizer[State]
+	Weights    []float64
+	limit      float64
+}
+
+func NewLinearEvaluator[State games.GameState](featurizer Featurizer[State], weights []float64) *LinearEvaluator[State] {
+	return &LinearEvaluator[State]{featurizer: featurizer, Weights: weights}
+}
+
+// Value is the predicted final Score of prospect, in points.
+func (e *LinearEvaluator[State]) Value(prospect games.Prospect[State]) float64 {
+	return e.value(e.featurizer.Features(prospect))
+}
+
+func (e *LinearEvaluator[State]) value(features []float64) float64 {
+	v := 0.0
+	for i, f := range features {
+		if i < len(e.Weights) {
+			v += e.Weights[i] * f
+		}
+	}
+	return v
+}
+
+// WithLimit keeps Evaluate just inside a Score of points either way, so that
+// no estimate outweighs a position that's actually been won. It should be set
+// to the Score of a win in games that have one; by default there's no limit.
+func (e *LinearEvaluator[State]) WithLimit(points float64) *LinearEvaluator[State] {
+	e.limit = points
+	return e
+}
+
+func (e *LinearEvaluator[State]) Evaluate(prospect games.Prospect[State]) int {
+	score := math.Round(e.Value(prospect) * games.EvaluationScale)
+	if e.limit > 0 {
+		bound := math.Round(e.limit*games.EvaluationScale) - 1
+		score = math.Max(-bound, math.Min(score, bound))
+	}
+	return int(score)
+}
+
+var weightsMagic = [4]byte{'M', 'M', 'X', 'W'}
+
+// WriteTo writes the limit and the weights as a short header followed by one
+// float64 per weight.
+func (e *LinearEvaluator[State]) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	if err := binary.Write(w, binary.LittleEndian, weightsMagic); err != nil {
+		return n, err
+	}
+	n += int64(len(weightsMagic))
+	if err := binary.Write(w, binary.LittleEndian, e.limit); err != nil {
+		return n, err
+	}
+	n += 8
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(e.Weights))); err != nil {
+		return n, err
+	}
+	n += 4
+
+	err := binary.Write(w, binary.LittleEndian, e.Weights)
+	if err == nil {
+		n += 8 * int64(len(e.Weights))
+	}
+	return n, err
+}
+
+// ReadLinearEvaluator reads an evaluator written by LinearEvaluator.WriteTo,
+// with its limit, for features from featurizer. The file has to hold exactly
+// one weight per feature.
+func ReadLinearEvaluator[State games.GameState](r io.Reader, featurizer Featurizer[State]) (*LinearEvaluator[State], error) {
+	var magic [4]byte
+	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != weightsMagic {
+		return nil, fmt.Errorf("training: not a weights file")
+	}
+
+	var limit float64
+	if err := binary.Read(r, binary.LittleEndian, &limit); err != nil {
+		return nil, err
+	}
+	if !(limit >= 0) || math.IsInf(limit, 1) {
+		return nil, fmt.Errorf("training: bad limit %v", limit)
+	}
+
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, err
+	}
+	if int64(count) != int64(featurizer.FeatureCount()) {
+		return nil, fmt.Errorf("training: file has %d weights for %d features", count, featurizer.FeatureCount())
+	}
+	weights := make([]float64, count)
+	if err := binary.Read(r, binary.LittleEndian, weights); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return NewLinearEvaluator(featurizer, weights).WithLimit(limit), nil
+}
+
+// Trainer fits a LinearEvaluator by temporal-difference learning, TD(lambda),
+// over games a Minimaxer using the evaluator plays against itself. After each
+// game, the prediction at every position it passed through is moved toward
+// the prediction at the next one, and the last toward the final Score, with
+// lambda deciding how far each correction carries back to earlier positions.
+type Trainer[State games.GameState] struct {
+	game      games.Game[State]
+	evaluator *LinearEvaluator[State]
+	depth     int
+	rate      float64
+	lambda    float64
+	epsilon   float64
+	rng       *rand.Rand
+
+	games int
+	// lastError is the mean squared difference between successive
+	// predictions in the last game played.
+	lastError float64
+}
+
+func NewTrainer[State games.GameState](game games.Game[State], featurizer Featurizer[State]) *Trainer[State] {
+	return &Trainer[State]{
+		game:      game,
+		evaluator: NewLinearEvaluator(featurizer, nil),
+		depth:     2,
+		rate:      0.01,
+		lambda:    0.7,
+		epsilon:   0.1,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// WithWeights starts training from weights instead of from all zeroes.
+func (t *Trainer[State]) WithWeights(weights []float64) *Trainer[State] {
+	t.evaluator.Weights = weights
+	return t
+}
+
+// WithLimit sets the limit of the evaluator being trained, as in
+// LinearEvaluator.WithLimit.
+func (t *Trainer[State]) WithLimit(points float64) *Trainer[State] {
+	t.evaluator.WithLimit(points)
+	return t
+}
+
+// WithSearchDepth sets the lookahead of the self-play Minimaxer.
+func (t *Trainer[State]) WithSearchDepth(depth int) *Trainer[State] {
+	t.depth = depth
+	return t
+}
+
+// WithLearningRate sets how far each update moves the weights.
+func (t *Trainer[State]) WithLearningRate(rate float64) *Trainer[State] {
+	t.rate = rate
+	return t
+}
+
+// WithLambda sets how much of each correction carries back to the positions
+// before it: 0 only updates the position just before, and 1 updates every
+// earlier position toward the final Score.
+func (t *Trainer[State]) WithLambda(lambda float64) *Trainer[State] {
+	t.lambda = lambda
+	return t
+}
+
+// WithExploration makes the self-play players choose a random move with
+// probability epsilon, so that training sees positions good play would avoid.
+func (t *Trainer[State]) WithExploration(epsilon float64) *Trainer[State] {
+	t.epsilon = epsilon
+	return t
+}
+
+// WithSeed makes every random choice in training, and so the weights it ends
+// with, come from a source seeded with seed.
+func (t *Trainer[State]) WithSeed(seed int64) *Trainer[State] {
+	t.rng = rand.New(rand.NewSource(seed))
+	return t
+}
+
+// Evaluator returns the evaluator being trained.
+func (t *Trainer[State]) Evaluator() *LinearEvaluator[State] {
+	return t.evaluator
+}
+
+// Train plays count games of self-play, updating the weights after each.
+func (t *Trainer[State]) Train(count int) {
+	for i := 0; i < count; i++ {
+		t.playGame()
+	}
+}
+
+// selfPlayTableSize keeps the self-play Minimaxers' tables small, since their
+// searches are shallow and allocating a table is most of the work.
+const selfPlayTableSize = 1 << 12
+
+func (t *Trainer[State]) playGame() {
+	var positions [][]float64
+	record := func(prospect games.Prospect[State]) {
+		positions = append(positions, t.evaluator.featurizer.Features(prospect))
+	}
+
+	players := [2]gameplay.Player[State]{}
+	for i := range players {
+		m := minimaxer.NewMinimaxer(t.game, t.depth).
+			WithAlphaBeta().
+			WithTableSize(selfPlayTableSize).
+			WithEvaluator(t.evaluator).
+			WithSeed(t.rng.Int63())
+		players[i] = &selfPlayer[State]{m: m, game: t.game, record: record, epsilon: t.epsilon, rng: t.rng}
+	}
+
+	gp := gameplay.NewGameplay(t.game, players[0], players[1]).WithSeed(t.rng.Int63())
+	score := float64(gp.Play(false))
+	t.update(positions, score)
+	t.games++
+}
+
+// update applies TD(lambda) to the features of the positions of one game,
+// which ended with score.
+func (t *Trainer[State]) update(positions [][]float64, score float64) {
+	if len(positions) == 0 {
+		return
+	}
+	for len(t.evaluator.Weights) < len(positions[0]) {
+		t.evaluator.Weights = append(t.evaluator.Weights, 0)
+	}
+
+	trace := make([]float64, len(t.evaluator.Weights))
+	squaredError := 0.0
+	for i, features := range positions {
+		next := score
+		if i+1 < len(positions) {
+			next = t.evaluator.value(positions[i+1])
+		}
+		delta := next - t.evaluator.value(features)
+		squaredError += delta * delta
+
+		for j := range trace {
+			trace[j] *= t.lambda
+			if j < len(features) {
+				trace[j] += features[j]
+			}
+		}
+		for j := range t.evaluator.Weights {
+			t.evaluator.Weights[j] += t.rate * delta * trace[j]
+		}
+	}
+	t.lastError = squaredError / float64(len(positions))
+}
+
+// Progress describes how training is going.
+func (t *Trainer[State]) Progress() string {
+	return fmt.Sprintf("After %d games, successive predictions differ by %.3f points on average.", t.games, math.Sqrt(t.lastError))
+}
+
+// selfPlayer records every position it's asked to move in before choosing,
+// and now and then plays a random move instead of the Minimaxer's.
+type selfPlayer[State games.GameState] struct {
+	m       *minimaxer.Minimaxer[State]
+	game    games.Game[State]
+	record  func(games.Prospect[State])
+	epsilon float64
+	rng     *rand.Rand
+}
+
+func (p *selfPlayer[State]) ChooseMove(prospect games.Prospect[State]) games.Move[State] {
+	p.record(prospect)
+	if p.rng.Float64() < p.epsilon {
+		moves := p.game.Describe(prospect).Moves
+		return moves[p.rng.Intn(len(moves))]
+	}
+	return p.m.ChooseMove(prospect)
+}
+
+func (p *selfPlayer[State]) Name() string {
+	return p.m.Name()
+}
+
+func (p *selfPlayer[State]) Comment() string {
+	return ""
+}
@@ -0,0 +1,50 @@
+package training_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/cstuartroe/minimax/connect_four"
+	"github.com/cstuartroe/minimax/games"
+	"github.com/cstuartroe/minimax/training"
+)
+
+func TestReadLinearEvaluator(t *testing.T) {
+	featurizer := training.Featurizer[connect_four.ConnectFourState](connect_four.Featurizer{})
+	evaluator := training.NewLinearEvaluator(featurizer, []float64{5, 0, 0, 0, 0, 0}).WithLimit(1)
+
+	var buf bytes.Buffer
+	if _, err := evaluator.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	written := buf.Bytes()
+
+	read, err := training.ReadLinearEvaluator(bytes.NewReader(written), featurizer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prospect := games.Prospect[connect_four.ConnectFourState]{State: connect_four.ConnectFour().InitialState(), FirstAgent: true}
+	if got, want := read.Evaluate(prospect), evaluator.Evaluate(prospect); got != want || got >= games.EvaluationScale {
+		t.Errorf("read evaluator gives %d, wrote one that gives %d", got, want)
+	}
+
+	// The header is the magic, the limit, then the weight count.
+	huge := append([]byte{}, written...)
+	binary.LittleEndian.PutUint32(huge[12:16], 1<<31-1)
+	short := append([]byte{}, written...)
+	binary.LittleEndian.PutUint32(short[12:16], 2)
+	corrupt := map[string][]byte{
+		"huge count":     huge,
+		"too few":        short,
+		"truncated":      written[:len(written)-1],
+		"header only":    written[:8],
+		"no weights":     written[:16],
+		"negative limit": append(append(append([]byte{}, written[:4]...), 0, 0, 0, 0, 0, 0, 0xf0, 0xbf), written[12:]...),
+	}
+	for name, data := range corrupt {
+		if _, err := training.ReadLinearEvaluator(bytes.NewReader(data), featurizer); err == nil {
+			t.Errorf("%s: read a corrupt weights file", name)
+		}
+	}
+}